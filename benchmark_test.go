@@ -27,3 +27,37 @@ func BenchmarkFmtErrorf(b *testing.B) {
 		_ = fmt.Errorf("%w, key1: %s, key2: %s", baseErr, val1, val2)
 	}
 }
+
+func BenchmarkWithLazy(b *testing.B) {
+	baseErr := New("benchmark error")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = With(baseErr, Lazy("key1", func() string { return "value1" }))
+	}
+}
+
+func BenchmarkWithStack(b *testing.B) {
+	baseErr := New("benchmark error")
+	field1 := String("key1", "value1")
+	field2 := String("key2", "value2")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = With(baseErr, Stack(), field1, field2)
+	}
+}
+
+func BenchmarkWithStackTraces(b *testing.B) {
+	baseErr := New("benchmark error")
+	field1 := String("key1", "value1")
+	field2 := String("key2", "value2")
+
+	SetStackTraces(true)
+	defer SetStackTraces(false)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = With(baseErr, field1, field2)
+	}
+}
@@ -0,0 +1,171 @@
+package errorc
+
+import (
+	"sync"
+	"time"
+)
+
+// Class classifies an error by whether the operation that failed with it can be retried.
+type Class uint8
+
+// Error classes.
+const (
+	// Unclassified is the class of errors that are not classified.
+	Unclassified Class = iota
+	// Retryable is the class of errors after which the operation can be retried, for
+	// example because a concurrent update made it fail.
+	Retryable
+	// Temporary is the class of errors caused by a condition expected to resolve itself,
+	// such as a timeout or an unavailable dependency. Temporary errors are retryable.
+	Temporary
+	// Permanent is the class of errors after which retrying the operation fails again,
+	// such as invalid input.
+	Permanent
+)
+
+var classNames = [...]string{
+	Unclassified: "Unclassified",
+	Retryable:    "Retryable",
+	Temporary:    "Temporary",
+	Permanent:    "Permanent",
+}
+
+// String returns the name of the class, for example "Retryable".
+func (c Class) String() string {
+	if int(c) < len(classNames) {
+		return classNames[c]
+	}
+	return "<unknown errorc.Class>"
+}
+
+// RetryAfterKey is the key of the field created by RetryAfter.
+const RetryAfterKey = "retry_after"
+
+// WithClass sets the class of an error created by New. It overrides the class set for its
+// namespace with SetNamespaceClass.
+func WithClass(c Class) Option {
+	return func(o *options) {
+		o.class = c
+	}
+}
+
+// namespaceClasses maps namespaces to the classes set with SetNamespaceClass.
+var namespaceClasses sync.Map
+
+// SetNamespaceClass sets the class of the errors created under ns or one of its children
+// without a class of their own, including errors created before the call. The class of
+// the nearest namespace wins, so a child namespace can override its parent. Setting
+// Unclassified removes the class of ns. Classes are usually set for the namespaces a
+// package owns, from the package declaring them.
+func SetNamespaceClass(ns Namespace, c Class) {
+	if c == Unclassified {
+		namespaceClasses.Delete(ns)
+		return
+	}
+	namespaceClasses.Store(ns, c)
+}
+
+// namespaceClass returns the class set for ns or its nearest ancestor.
+func namespaceClass(ns Namespace) Class {
+	for ; ns != ""; ns = ns.Parent() {
+		if c, ok := namespaceClasses.Load(ns); ok {
+			return c.(Class)
+		}
+	}
+	return Unclassified
+}
+
+// Classify creates a field setting the class of the error returned by With, overriding the
+// class of the error it wraps:
+//
+//	return With(err, Classify(Permanent), String("reason", "quota exceeded"))
+//
+// The field is not rendered and is not returned by Fields or Lookup.
+func Classify(c Class) field {
+	if int(c) < len(classFields) {
+		return classFields[c]
+	}
+	return classField(c)
+}
+
+// classFields holds the fields of the known classes, so that Classify does not allocate.
+var classFields = [...]field{
+	Unclassified: classField(Unclassified),
+	Retryable:    classField(Retryable),
+	Temporary:    classField(Temporary),
+	Permanent:    classField(Permanent),
+}
+
+func classField(c Class) field {
+	return func() Field {
+		return Field{kind: kindClass, num: uint64(c)}
+	}
+}
+
+// RetryAfter creates a field holding how long to wait before retrying the operation,
+// for example as requested by a rate-limited dependency. The field has the key
+// RetryAfterKey and is rendered like a Duration field. It can be read with RetryDelay.
+func RetryAfter(d time.Duration) field {
+	return Duration(RetryAfterKey, d)
+}
+
+// RetryDelay returns the duration set with RetryAfter for err or any error in its Unwrap
+// chain. If several are set, the most recently attached one wins, like in Lookup.
+func RetryDelay(err error) (time.Duration, bool) {
+	f, ok := Lookup(err, RetryAfterKey)
+	if !ok || f.Kind() != KindDuration {
+		return 0, false
+	}
+	return f.Duration(), true
+}
+
+// ClassOf returns the class of the nearest classified error in the tree of errors wrapped
+// by err. The tree is traversed in the same depth-first order as errors.As, so a class set
+// with Classify overrides the class of the errors it wraps. An error is classified by:
+//   - the Classify fields attached by With; the last one wins;
+//   - the WithClass option of an error created by New, or else the class of its namespace
+//     set with SetNamespaceClass;
+//   - a Temporary() bool or a Timeout() bool method returning true, implemented for
+//     example by net.Error and context.DeadlineExceeded: such errors are Temporary.
+//
+// It returns Unclassified if err is nil or no error in its tree is classified.
+func ClassOf(err error) Class {
+	c := Unclassified
+	walk(err, func(err error) bool {
+		c = classOf(err)
+		return c != Unclassified
+	})
+	return c
+}
+
+// classOf returns the class of err itself, ignoring the errors it wraps.
+func classOf(err error) Class {
+	switch e := err.(type) {
+	case *errorWithFields:
+		if e.class != Unclassified {
+			return e.class
+		}
+	case *sentinel:
+		if e.class != Unclassified {
+			return e.class
+		}
+		return namespaceClass(e.ns)
+	}
+	if t, ok := err.(interface{ Temporary() bool }); ok && t.Temporary() {
+		return Temporary
+	}
+	if t, ok := err.(interface{ Timeout() bool }); ok && t.Timeout() {
+		return Temporary
+	}
+	return Unclassified
+}
+
+// IsRetryable reports whether the operation that failed with err can be retried, that is
+// whether ClassOf(err) is Retryable or Temporary.
+func IsRetryable(err error) bool {
+	switch ClassOf(err) {
+	case Retryable, Temporary:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package errorc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+// setNamespaceClass sets the class of ns for the duration of the test.
+func setNamespaceClass(t *testing.T, ns Namespace, c Class) {
+	t.Helper()
+	SetNamespaceClass(ns, c)
+	t.Cleanup(func() { SetNamespaceClass(ns, Unclassified) })
+}
+
+type temporaryError struct{ temporary bool }
+
+func (e temporaryError) Error() string   { return "temporary" }
+func (e temporaryError) Temporary() bool { return e.temporary }
+
+func TestClassOf(t *testing.T) {
+	const ns Namespace = "class_test"
+	setNamespaceClass(t, ns, Temporary)
+	setNamespaceClass(t, ns.Child("strict"), Permanent)
+
+	errConflict := New("conflict", WithClass(Retryable))
+	errInvalid := New("invalid", WithClass(Permanent))
+
+	tests := []struct {
+		name string
+		err  error
+		want Class
+	}{
+		{"nil", nil, Unclassified},
+		{"unclassified", New("plain"), Unclassified},
+		{"option", errConflict, Retryable},
+		{"option permanent", errInvalid, Permanent},
+		{"wrapped", fmt.Errorf("saving: %w", With(errConflict, String("id", "1"))), Retryable},
+		{"metadata is ignored", New("x", WithMetadata("retryable", true)), Unclassified},
+		{"namespace default", ns.NewError("unavailable"), Temporary},
+		{"child namespace inherits", ns.Child("s3").NewError("unavailable"), Temporary},
+		{"child namespace overrides", ns.Child("strict").NewError("rejected"), Permanent},
+		{"option overrides namespace", ns.NewError("invalid", WithClass(Permanent)), Permanent},
+		{"With overrides sentinel", With(errConflict, Classify(Permanent)), Permanent},
+		{"last Classify wins", With(errInvalid, Classify(Permanent), Classify(Temporary)), Temporary},
+		{"outer With wins", With(With(errInvalid, Classify(Permanent)), Classify(Retryable)), Retryable},
+		{"Temporary method", temporaryError{true}, Temporary},
+		{"Temporary false", temporaryError{false}, Unclassified},
+		{"Timeout method", fmt.Errorf("dial: %w", os.ErrDeadlineExceeded), Temporary},
+		{"context deadline", context.DeadlineExceeded, Temporary},
+		{"context canceled", context.Canceled, Unclassified},
+		{"net error", &net.DNSError{Err: "timeout", IsTimeout: true}, Temporary},
+		{"joined", errors.Join(New("plain"), errConflict), Retryable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassOf(tt.err); got != tt.want {
+				t.Fatalf("ClassOf = %s, want %s", got, tt.want)
+			}
+			if got, want := IsRetryable(tt.err), tt.want == Retryable || tt.want == Temporary; got != want {
+				t.Fatalf("IsRetryable = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestClassify_hidden(t *testing.T) {
+	err := With(New("conflict"), Classify(Retryable), String("id", "1"))
+	if got, want := err.Error(), "conflict, id: 1"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if fields := Fields(err); len(fields) != 1 {
+		t.Errorf("Fields returned %d fields, want 1", len(fields))
+	}
+	if got, want := fmt.Sprintf("%#v", err), `errorc.With(errorc.New("conflict"), errorc.Classify(errorc.Retryable), errorc.String("id", "1"))`; got != want {
+		t.Errorf("%%#v = %s, want %s", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", err), "conflict, id: 1\n\tid: 1\ncaused by: conflict"; got != want {
+		t.Errorf("%%+v = %q, want %q", got, want)
+	}
+}
+
+func TestClassOf_lazy(t *testing.T) {
+	calls := 0
+	err := With(New("conflict"), Lazy("dump", func() string { calls++; return "" }))
+	err = With(err, Classify(Retryable))
+	if !IsRetryable(err) || calls != 0 {
+		t.Fatalf("IsRetryable = %v after %d calls, want true after 0 calls", IsRetryable(err), calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := With(New("rate limited", WithClass(Temporary)), RetryAfter(5*time.Second))
+	if got, want := err.Error(), "rate limited, retry_after: 5s"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if d, ok := RetryDelay(fmt.Errorf("call: %w", err)); !ok || d != 5*time.Second {
+		t.Errorf("RetryDelay = %v, %v, want 5s, true", d, ok)
+	}
+	if d, ok := RetryDelay(With(err, RetryAfter(time.Second))); !ok || d != time.Second {
+		t.Errorf("RetryDelay of the outer layer = %v, %v, want 1s, true", d, ok)
+	}
+	if _, ok := RetryDelay(With(New("x"), String(RetryAfterKey, "5s"))); ok {
+		t.Errorf("RetryDelay of a string field = true, want false")
+	}
+	if _, ok := RetryDelay(New("x")); ok {
+		t.Errorf("RetryDelay without RetryAfter = true, want false")
+	}
+}
+
+func TestClass_String(t *testing.T) {
+	if got := Temporary.String(); got != "Temporary" {
+		t.Errorf("Temporary.String() = %q", got)
+	}
+	if got := Class(42).String(); got != "<unknown errorc.Class>" {
+		t.Errorf("Class(42).String() = %q", got)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// catalog is the declarative description of the errors of one namespace.
+type catalog struct {
+	// Package is the name of the generated Go package.
+	Package string `yaml:"package" json:"package"`
+	// Namespace is the errorc namespace of the errors, for example "storage" or "storage/s3".
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// Registry, if set, is the name of a generated errorc.Registry variable the namespace is bound to.
+	Registry string      `yaml:"registry" json:"registry"`
+	Errors   []errorSpec `yaml:"errors" json:"errors"`
+}
+
+// errorSpec describes a single sentinel error.
+type errorSpec struct {
+	// Name is the Go name of the error, without the "Err" prefix. It defaults to the
+	// code converted to CamelCase, for example "ReadFailed" for "read_failed".
+	Name        string `yaml:"name" json:"name"`
+	Code        string `yaml:"code" json:"code"`
+	Message     string `yaml:"message" json:"message"`
+	Description string `yaml:"description" json:"description"`
+	HTTPStatus  int    `yaml:"http_status" json:"http_status"`
+	// GRPCCode is the name of a google.golang.org/grpc/codes code, for example "Unavailable".
+	GRPCCode string `yaml:"grpc_code" json:"grpc_code"`
+	// Retryable, if set, classifies the error as errorc.Retryable if true or errorc.Permanent
+	// if false. If unset, the error is not classified by its metadata.
+	Retryable *bool `yaml:"retryable" json:"retryable"`
+	// Defaults are fields attached by the generated constructor with constant values.
+	Defaults map[string]any `yaml:"defaults" json:"defaults"`
+	// Fields are the fields the generated constructor requires as parameters.
+	Fields []fieldSpec `yaml:"fields" json:"fields"`
+}
+
+// fieldSpec describes a required field.
+type fieldSpec struct {
+	Key  string `yaml:"key" json:"key"`
+	Type string `yaml:"type" json:"type"`
+}
+
+// fieldTypes maps the field types accepted in catalogs to Go types and errorc helpers.
+var fieldTypes = map[string]struct{ goType, helper string }{
+	"string":   {"string", "String"},
+	"int":      {"int", "Int"},
+	"int64":    {"int64", "Int64"},
+	"uint64":   {"uint64", "Uint64"},
+	"float64":  {"float64", "Float64"},
+	"bool":     {"bool", "Bool"},
+	"duration": {"time.Duration", "Duration"},
+	"time":     {"time.Time", "Time"},
+	"bytes":    {"[]byte", "Bytes"},
+	"error":    {"error", "Error"},
+	"any":      {"any", "Any"},
+}
+
+// grpcCodes holds the names of the google.golang.org/grpc/codes codes, as returned by
+// codes.Code.String.
+var grpcCodes = map[string]bool{
+	"OK": true, "Canceled": true, "Unknown": true, "InvalidArgument": true,
+	"DeadlineExceeded": true, "NotFound": true, "AlreadyExists": true,
+	"PermissionDenied": true, "ResourceExhausted": true, "FailedPrecondition": true,
+	"Aborted": true, "OutOfRange": true, "Unimplemented": true, "Internal": true,
+	"Unavailable": true, "DataLoss": true, "Unauthenticated": true,
+}
+
+// loadCatalog reads a catalog from a YAML or, if the file name ends with ".json", JSON file.
+func loadCatalog(path string) (*catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c catalog
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		dec.UseNumber()
+		err = dec.Decode(&c)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := c.normalize(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// normalize fills defaults and validates the catalog.
+func (c *catalog) normalize() error {
+	var errs []error
+	if !token.IsIdentifier(c.Package) {
+		errs = append(errs, fmt.Errorf("package %q is not a valid Go identifier", c.Package))
+	}
+	if c.Namespace == "" {
+		errs = append(errs, errors.New("namespace is required"))
+	}
+	if c.Registry != "" && !token.IsIdentifier(c.Registry) {
+		errs = append(errs, fmt.Errorf("registry %q is not a valid Go identifier", c.Registry))
+	}
+
+	codes := make(map[string]bool, len(c.Errors))
+	names := make(map[string]bool, len(c.Errors))
+	for i := range c.Errors {
+		e := &c.Errors[i]
+		if e.Code == "" {
+			errs = append(errs, fmt.Errorf("errors[%d]: code is required", i))
+			continue
+		}
+		if codes[e.Code] {
+			errs = append(errs, fmt.Errorf("errors[%d]: duplicate code %q", i, e.Code))
+		}
+		codes[e.Code] = true
+
+		if e.Name == "" {
+			e.Name = camelCase(e.Code, true)
+		}
+		e.Description = strings.TrimSpace(e.Description)
+		if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+			errs = append(errs, fmt.Errorf("errors[%d]: name %q is not an exported Go identifier", i, e.Name))
+		}
+		if names[e.Name] {
+			errs = append(errs, fmt.Errorf("errors[%d]: duplicate name %q", i, e.Name))
+		}
+		names[e.Name] = true
+		if e.GRPCCode != "" && !grpcCodes[e.GRPCCode] {
+			errs = append(errs, fmt.Errorf("errors[%d]: grpc_code %q is not a gRPC code name, like \"Unavailable\"", i, e.GRPCCode))
+		}
+
+		params := make(map[string]bool, len(e.Fields))
+		for j, f := range e.Fields {
+			if f.Key == "" {
+				errs = append(errs, fmt.Errorf("errors[%d].fields[%d]: key is required", i, j))
+			}
+			if params[paramName(f.Key)] {
+				errs = append(errs, fmt.Errorf("errors[%d].fields[%d]: duplicate key %q", i, j, f.Key))
+			}
+			params[paramName(f.Key)] = true
+			if _, ok := fieldTypes[f.Type]; !ok {
+				errs = append(errs, fmt.Errorf("errors[%d].fields[%d]: unknown type %q", i, j, f.Type))
+			}
+		}
+		for _, key := range e.sortedDefaultKeys() {
+			if _, err := defaultField(key, e.Defaults[key]); err != nil {
+				errs = append(errs, fmt.Errorf("errors[%d].defaults: %w", i, err))
+			}
+			for _, f := range e.Fields {
+				if f.Key == key {
+					errs = append(errs, fmt.Errorf("errors[%d].defaults: key %q is also a key of fields", i, key))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// needsTime reports whether the generated code refers to the time package.
+func (c *catalog) needsTime() bool {
+	for _, e := range c.Errors {
+		for _, f := range e.Fields {
+			if f.Type == "duration" || f.Type == "time" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortedDefaultKeys returns the keys of the default fields of e, sorted.
+func (e *errorSpec) sortedDefaultKeys() []string {
+	keys := make([]string, 0, len(e.Defaults))
+	for key := range e.Defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedDefaults returns the default fields of e sorted by key, as errorc helper calls.
+func (e *errorSpec) sortedDefaults() []string {
+	keys := e.sortedDefaultKeys()
+	calls := make([]string, len(keys))
+	for i, key := range keys {
+		calls[i], _ = defaultField(key, e.Defaults[key])
+	}
+	return calls
+}
+
+// defaultField returns the errorc helper call creating a field with a constant value.
+func defaultField(key string, value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("errorc.String(%q, %q)", key, v), nil
+	case bool:
+		return fmt.Sprintf("errorc.Bool(%q, %t)", key, v), nil
+	case int:
+		return fmt.Sprintf("errorc.Int64(%q, %d)", key, v), nil
+	case uint64:
+		return fmt.Sprintf("errorc.Uint64(%q, %d)", key, v), nil
+	case float64:
+		return fmt.Sprintf("errorc.Float64(%q, %v)", key, v), nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return fmt.Sprintf("errorc.Int64(%q, %d)", key, n), nil
+		}
+		if f, err := v.Float64(); err == nil {
+			return fmt.Sprintf("errorc.Float64(%q, %v)", key, f), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported value %v of type %T for key %q", value, value, key)
+}
+
+// goInitialisms are written in upper case in generated identifiers.
+var goInitialisms = map[string]bool{
+	"api": true, "http": true, "id": true, "ip": true, "json": true,
+	"sql": true, "uid": true, "url": true, "uuid": true,
+}
+
+// camelCase converts a snake_case, kebab-case, or dotted name to CamelCase,
+// or to lowerCamelCase if exported is false.
+func camelCase(s string, exported bool) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == ' ' || r == '/'
+	})
+
+	var b strings.Builder
+	for i, w := range words {
+		switch {
+		case i == 0 && !exported:
+			b.WriteString(strings.ToLower(w))
+		case goInitialisms[strings.ToLower(w)]:
+			b.WriteString(strings.ToUpper(w))
+		default:
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	return b.String()
+}
+
+// paramName returns the name of the constructor parameter for a field key.
+func paramName(key string) string {
+	name := camelCase(key, false)
+	if name == "" || token.IsKeyword(name) || !token.IsIdentifier(name) {
+		return "v" + camelCase(key, true)
+	}
+	return name
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+)
+
+// Metadata keys used by the generated declarations. They match the keys read by the
+// errorc HTTP and gRPC integrations.
+const (
+	metadataHTTPStatus = "http.status"
+	metadataGRPCCode   = "grpc.code"
+)
+
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{
+	"comment": comment,
+	"options": options,
+	"params":  params,
+	"fields":  fields,
+}).Parse(`// Code generated by errorc-gen from {{.Source}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .NeedsTime}}
+	"time"
+{{end}}
+	"github.com/ygrebnov/errorc"
+)
+{{with .Registry}}
+// {{.}} records the errors declared in this package.
+var {{.}} = errorc.NewRegistry()
+{{end}}
+// namespace is the namespace of the errors declared in this package.
+var namespace = {{if .Registry}}{{.Registry}}.Namespace({{printf "%q" .Namespace}}){{else}}errorc.Namespace({{printf "%q" .Namespace}}){{end}}
+
+// Sentinel errors. Match them with errors.Is.
+var (
+{{- range .Errors}}
+{{comment (printf "Err%s is the %q error." .Name .Code) .Description}}
+	Err{{.Name}} = namespace.NewError({{printf "%q" .Message}}{{options .}})
+{{- end}}
+)
+{{range .Errors}}
+// {{.Name}}Error returns Err{{.Name}} with its fields attached.
+func {{.Name}}Error({{params .}}) error {
+	return errorc.With(Err{{.Name}}{{fields .}})
+}
+{{end}}`))
+
+type goData struct {
+	*catalog
+	Source    string
+	NeedsTime bool
+}
+
+// generateGo returns the Go source declaring the errors of c.
+func generateGo(c *catalog, source string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := goTemplate.Execute(&buf, goData{catalog: c, Source: source, NeedsTime: c.needsTime()}); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+// comment formats a doc comment for a declaration indented by one tab.
+func comment(summary, description string) string {
+	lines := []string{"\t// " + summary}
+	if description != "" {
+		lines = append(lines, "\t//")
+		for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+			lines = append(lines, strings.TrimRight("\t// "+line, " "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// options returns the errorc options of a NewError call, one per line.
+func options(e errorSpec) string {
+	opts := []string{fmt.Sprintf("errorc.WithCode(%q)", e.Code)}
+	if e.Description != "" {
+		opts = append(opts, fmt.Sprintf("errorc.WithDescription(%q)", e.Description))
+	}
+	if e.HTTPStatus != 0 {
+		opts = append(opts, fmt.Sprintf("errorc.WithMetadata(%q, %d)", metadataHTTPStatus, e.HTTPStatus))
+	}
+	if e.GRPCCode != "" {
+		opts = append(opts, fmt.Sprintf("errorc.WithMetadata(%q, %q)", metadataGRPCCode, e.GRPCCode))
+	}
+	if e.Retryable != nil {
+		class := "errorc.Permanent"
+		if *e.Retryable {
+			class = "errorc.Retryable"
+		}
+		opts = append(opts, "errorc.WithClass("+class+")")
+	}
+	return argLines(opts, "\t")
+}
+
+// params returns the parameter list of the constructor of e.
+func params(e errorSpec) string {
+	ps := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		ps[i] = paramName(f.Key) + " " + fieldTypes[f.Type].goType
+	}
+	return strings.Join(ps, ", ")
+}
+
+// fields returns the field helper calls of the constructor of e, one per line.
+func fields(e errorSpec) string {
+	calls := e.sortedDefaults()
+	for _, f := range e.Fields {
+		calls = append(calls, fmt.Sprintf("errorc.%s(%q, %s)", fieldTypes[f.Type].helper, f.Key, paramName(f.Key)))
+	}
+	return argLines(calls, "\t")
+}
+
+// argLines formats the trailing arguments of a call whose first argument is already written:
+// inline if there is a single one, otherwise one per line, indented one level deeper than indent.
+func argLines(args []string, indent string) string {
+	switch len(args) {
+	case 0:
+		return ""
+	case 1:
+		return ", " + args[0]
+	}
+	var b strings.Builder
+	for _, arg := range args {
+		b.WriteString(",\n\t" + indent + arg)
+	}
+	b.WriteString(",\n" + indent)
+	return b.String()
+}
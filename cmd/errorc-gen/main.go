@@ -0,0 +1,88 @@
+// Command errorc-gen generates errorc sentinel errors from a declarative catalog.
+//
+// Usage:
+//
+//	errorc-gen -in errors.yaml [-out errors_gen.go] [-doc ERRORS.md]
+//
+// The catalog is a YAML file, or a JSON file if its name ends with ".json":
+//
+//	package: storage
+//	namespace: storage
+//	registry: Catalog        # optional: bind the namespace to a generated errorc.Registry
+//	errors:
+//	  - code: read_failed
+//	    message: read failed
+//	    description: The object could not be read from the backend.
+//	    http_status: 503
+//	    grpc_code: Unavailable
+//	    retryable: true
+//	    defaults:
+//	      component: s3
+//	    fields:
+//	      - key: bucket
+//	        type: string
+//	      - key: attempts
+//	        type: int
+//
+// For each error, errorc-gen declares a sentinel, ErrReadFailed, created with
+// Namespace.NewError and the errorc.WithCode, errorc.WithDescription, errorc.WithMetadata
+// and errorc.WithClass options, and a constructor, ReadFailedError(bucket string, attempts int) error, wrapping
+// the sentinel with the default and required fields. Field types are string, int, int64,
+// uint64, float64, bool, duration, time, bytes, error, and any. A grpc_code must be the name
+// of a google.golang.org/grpc/codes code. A retryable value of true or false sets the class
+// of the error to errorc.Retryable or errorc.Permanent with errorc.WithClass; an omitted one leaves it unclassified.
+//
+// It is meant to be run by go generate:
+//
+//	//go:generate go run github.com/ygrebnov/errorc/cmd/errorc-gen -in errors.yaml -doc ERRORS.md
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "errorc-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("errorc-gen", flag.ContinueOnError)
+	in := fs.String("in", "", "catalog file (YAML, or JSON if the name ends with .json)")
+	out := fs.String("out", "", "generated Go file (default: the catalog name with the _gen.go suffix)")
+	doc := fs.String("doc", "", "generated markdown documentation file (default: none)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		fs.Usage()
+		return fmt.Errorf("-in is required")
+	}
+	if *out == "" {
+		*out = strings.TrimSuffix(*in, filepath.Ext(*in)) + "_gen.go"
+	}
+
+	c, err := loadCatalog(*in)
+	if err != nil {
+		return err
+	}
+
+	src, err := generateGo(c, filepath.Base(*in))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		return err
+	}
+
+	if *doc != "" {
+		return os.WriteFile(*doc, generateMarkdown(c), 0o644)
+	}
+	return nil
+}
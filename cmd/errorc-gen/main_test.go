@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRun(t *testing.T) {
+	for _, in := range []string{"testdata/storage.yaml", "testdata/storage.json"} {
+		t.Run(filepath.Base(in), func(t *testing.T) {
+			dir := t.TempDir()
+			out := filepath.Join(dir, "storage_gen.go")
+			doc := filepath.Join(dir, "ERRORS.md")
+			if err := run([]string{"-in", in, "-out", out, "-doc", doc}); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+
+			// Both catalog formats produce the same output, except for the source name.
+			assertGolden(t, out, "testdata/storage_gen.go.golden", filepath.Base(in), "storage.yaml")
+			assertGolden(t, doc, "testdata/ERRORS.md.golden", "", "")
+		})
+	}
+}
+
+func TestLoadCatalog_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		catalog string
+		want    string
+	}{
+		{"unknown key", "package: p\nnamespace: n\nerrors:\n  - code: c\n    colour: red\n", "field colour not found"},
+		{"invalid package", "package: 1p\nnamespace: n\n", `package "1p" is not a valid Go identifier`},
+		{"missing namespace", "package: p\n", "namespace is required"},
+		{"missing code", "package: p\nnamespace: n\nerrors:\n  - message: m\n", "errors[0]: code is required"},
+		{"duplicate code", "package: p\nnamespace: n\nerrors:\n  - code: c\n  - code: c\n", `errors[1]: duplicate code "c"`},
+		{"unexported name", "package: p\nnamespace: n\nerrors:\n  - code: c\n    name: lower\n", `name "lower" is not an exported Go identifier`},
+		{"unknown field type", "package: p\nnamespace: n\nerrors:\n  - code: c\n    fields:\n      - key: k\n        type: complex\n", `unknown type "complex"`},
+		{"duplicate field", "package: p\nnamespace: n\nerrors:\n  - code: c\n    fields:\n      - {key: k, type: int}\n      - {key: k, type: int}\n", `duplicate key "k"`},
+		{"unsupported default", "package: p\nnamespace: n\nerrors:\n  - code: c\n    defaults:\n      k: [1]\n", `unsupported value [1]`},
+		{"unknown grpc code", "package: p\nnamespace: n\nerrors:\n  - code: c\n    grpc_code: Unavailble\n", `grpc_code "Unavailble" is not a gRPC code name`},
+		{"default clashing with a field", "package: p\nnamespace: n\nerrors:\n  - code: c\n    defaults:\n      k: v\n    fields:\n      - {key: k, type: string}\n",
+			`key "k" is also a key of fields`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "errors.yaml")
+			if err := os.WriteFile(path, []byte(tt.catalog), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadCatalog(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("loadCatalog() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	tests := []struct {
+		in       string
+		exported bool
+		want     string
+	}{
+		{"read_failed", true, "ReadFailed"},
+		{"user-id", true, "UserID"},
+		{"http.status", false, "httpStatus"},
+		{"object_url", false, "objectURL"},
+	}
+	for _, tt := range tests {
+		if got := camelCase(tt.in, tt.exported); got != tt.want {
+			t.Errorf("camelCase(%q, %v) = %q, want %q", tt.in, tt.exported, got, tt.want)
+		}
+	}
+	if got := paramName("type"); got != "vType" {
+		t.Errorf("paramName(type) = %q, want vType", got)
+	}
+}
+
+func assertGolden(t *testing.T, path, golden, old, new string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old != "" {
+		got = []byte(strings.ReplaceAll(string(got), old, new))
+	}
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("%s differs from %s:\n%s", path, golden, got)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// generateMarkdown returns a markdown document describing the errors of c.
+func generateMarkdown(c *catalog) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# `%s` errors\n\n", c.Namespace)
+	b.WriteString("| Code | Message | HTTP status | gRPC code | Retryable | Fields |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, e := range c.Errors {
+		fmt.Fprintf(&b, "| [`%s`](#%s) | %s | %s | %s | %s | %s |\n",
+			e.Code, anchor(e.Code), escapeCell(e.Message), orDash(e.HTTPStatus != 0, fmt.Sprint(e.HTTPStatus)),
+			orDash(e.GRPCCode != "", "`"+e.GRPCCode+"`"), yesNo(e.Retryable), fieldList(e))
+	}
+
+	for _, e := range c.Errors {
+		fmt.Fprintf(&b, "\n## %s\n\n", e.Code)
+		fmt.Fprintf(&b, "`Err%s`: %s\n", e.Name, e.Message)
+		if e.Description != "" {
+			fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(e.Description))
+		}
+	}
+	return b.Bytes()
+}
+
+func fieldList(e errorSpec) string {
+	if len(e.Fields) == 0 {
+		return "-"
+	}
+	items := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		items[i] = fmt.Sprintf("`%s` (%s)", f.Key, f.Type)
+	}
+	return strings.Join(items, ", ")
+}
+
+// anchor returns the GitHub anchor of a heading.
+func anchor(heading string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "-", ".", "", "/", "").Replace(heading))
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func orDash(ok bool, s string) string {
+	if !ok {
+		return "-"
+	}
+	return s
+}
+
+// yesNo returns "yes" or "no" for a set value, and "-" for an unset one.
+func yesNo(b *bool) string {
+	switch {
+	case b == nil:
+		return "-"
+	case *b:
+		return "yes"
+	}
+	return "no"
+}
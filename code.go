@@ -0,0 +1,75 @@
+package errorc
+
+// WithCode sets a stable, machine-readable code for an error created by New,
+// for example "read_failed". Together with the namespace set by WithNamespace,
+// the code identifies the error across process boundaries: errors.Is reports
+// that two errors created by New match if their namespaces and codes are equal,
+// even if they are distinct values or have different messages.
+// An empty code is ignored.
+func WithCode(code string) Option {
+	return func(o *options) {
+		if code != "" {
+			o.code = code
+		}
+	}
+}
+
+// Code returns the code of the nearest error in the tree of errors wrapped by err that
+// was created by New with WithCode. The tree is traversed in the same depth-first order
+// as errors.As. It returns an empty string if there is none.
+func Code(err error) string {
+	if s := codedSentinel(err); s != nil {
+		return s.code
+	}
+	return ""
+}
+
+// Is reports whether target is an error created by New with the same
+// namespace and a non-empty code equal to the code of e, or a non-empty
+// Namespace equal to the namespace of e or to one of its ancestors.
+func (e *sentinel) Is(target error) bool {
+	switch t := target.(type) {
+	case *sentinel:
+		return e.code != "" && e.code == t.code && e.ns == t.ns
+	case Namespace:
+		return t.includes(e.ns)
+	}
+	return false
+}
+
+// codedSentinel returns the nearest error with a code in the tree of errors wrapped by err.
+func codedSentinel(err error) *sentinel {
+	var found *sentinel
+	walk(err, func(err error) bool {
+		if s, ok := err.(*sentinel); ok && s.code != "" {
+			found = s
+			return true
+		}
+		return false
+	})
+	return found
+}
+
+// walk calls fn for err and the errors it wraps, depth-first, in the order used by
+// errors.Is and errors.As, until fn returns true. It reports whether fn returned true.
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if fn(err) {
+			return true
+		}
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, err := range x.Unwrap() {
+				if walk(err, fn) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package errorc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWithCode(t *testing.T) {
+	storage := Namespace("storage")
+	errReadFailed := storage.NewError("read failed", WithCode("read_failed"))
+
+	if got := errReadFailed.Error(); got != "storage: read failed" {
+		t.Fatalf("Error() = %q, want 'storage: read failed'", got)
+	}
+	if got := New("", WithNamespace("storage"), WithCode("read_failed")).Error(); got != "storage: read_failed" {
+		t.Fatalf("Error() = %q, want the code used as message", got)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"same value", errReadFailed, true},
+		{"wrapped", With(fmt.Errorf("load: %w", errReadFailed), String("k", "v")), true},
+		{"same namespace and code", New("another message", WithNamespace("storage"), WithCode("read_failed")), true},
+		{"different code", storage.NewError("read failed", WithCode("write_failed")), false},
+		{"different namespace", New("read failed", WithCode("read_failed")), false},
+		{"no code", storage.NewError("read failed"), false},
+		{"plain error", errors.New("storage: read failed"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, errReadFailed); got != tt.want {
+				t.Fatalf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	uncoded := New("x")
+	if errors.Is(New("x"), uncoded) {
+		t.Fatalf("errors without code must match by identity only")
+	}
+}
+
+func TestCode(t *testing.T) {
+	inner := New("not found", WithCode("not_found"))
+	outer := New("load failed", WithCode("load_failed"))
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"no code", New("x"), ""},
+		{"direct", inner, "not_found"},
+		{"wrapped", With(fmt.Errorf("load: %w", inner), String("k", "v")), "not_found"},
+		{"nearest", fmt.Errorf("%w: %w", outer, inner), "load_failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.want {
+				t.Fatalf("Code() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCode_JSONAndFormat(t *testing.T) {
+	err := With(New("read failed", WithNamespace("storage"), WithNamespace("env"), WithCode("read_failed")), String("k", "v"))
+
+	b, jerr := JSON(err)
+	if jerr != nil {
+		t.Fatalf("JSON() error = %v", jerr)
+	}
+	want := `{"message":"env: storage: read failed","namespace":"env/storage","code":"read_failed","fields":{"k":"v"}}`
+	if string(b) != want {
+		t.Fatalf("JSON() = %s\nwant %s", b, want)
+	}
+
+	wantGo := `errorc.With(errorc.New("read failed", errorc.WithNamespace("storage"), errorc.WithNamespace("env"), errorc.WithCode("read_failed")), errorc.String("k", "v"))`
+	if got := fmt.Sprintf("%#v", err); got != wantGo {
+		t.Fatalf("%%#v = %s\nwant %s", got, wantGo)
+	}
+}
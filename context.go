@@ -0,0 +1,144 @@
+package errorc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// contextKey is the key of the fields stored in a context by ContextWith.
+type contextKey struct{}
+
+var (
+	// extractorsMu serializes RegisterContextExtractor calls.
+	extractorsMu sync.Mutex
+	// extractors holds the registered extractors. The slice is replaced, never modified.
+	extractors atomic.Pointer[[]func(context.Context) []Field]
+)
+
+// RegisterContextExtractor registers a function returning fields extracted from a context,
+// such as trace and span IDs or the authenticated principal stored under the application's
+// own context keys. WithContext calls every registered extractor, in registration order,
+// and attaches their fields before those stored with ContextWith, ignoring zero Fields.
+// Extractors must be safe for concurrent use and should be cheap, since they run for every
+// WithContext call.
+//
+// Extractors cannot be removed, so they are usually registered from an init function of
+// the package owning the context keys. A nil fn is ignored.
+func RegisterContextExtractor(fn func(ctx context.Context) []Field) {
+	if fn == nil {
+		return
+	}
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	var fns []func(context.Context) []Field
+	if p := extractors.Load(); p != nil {
+		fns = append(fns, *p...)
+	}
+	fns = append(fns, fn)
+	extractors.Store(&fns)
+}
+
+// ContextWith returns a copy of ctx carrying the given fields in addition to those already
+// stored in ctx, such as a request ID or a tenant. WithContext attaches them to errors.
+// Nil fields are ignored. If no non-nil fields are provided, it returns ctx.
+func ContextWith(ctx context.Context, fields ...field) context.Context {
+	stored, _ := ctx.Value(contextKey{}).([]field)
+	merged := stored[:len(stored):len(stored)]
+	for _, f := range fields {
+		if f != nil {
+			merged = append(merged, f)
+		}
+	}
+	if len(merged) == len(stored) {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, merged)
+}
+
+// WithContext works like With, attaching the fields returned by the extractors registered
+// with RegisterContextExtractor and the fields stored in ctx with ContextWith before the
+// given fields:
+//
+//	ctx = ContextWith(ctx, String("request_id", id))
+//	...
+//	return WithContext(ctx, ErrNotFound, String("key", key))
+//	// not found, request_id: 42, key: k
+//
+// Fields are deduplicated by key: if several fields have the same non-empty key, only the
+// last one is kept, so the given fields override those of ctx. Keys are compared without
+// computing the values of lazy fields.
+func WithContext(ctx context.Context, err error, fields ...field) error {
+	if err == nil {
+		return nil
+	}
+	merged := contextFields(ctx)
+	if len(merged) == 0 {
+		merged = fields
+	} else {
+		merged = append(merged, fields...)
+	}
+	e := &errorWithFields{e: err}
+	if !e.add(dedup(merged)) {
+		return err
+	}
+	return e
+}
+
+// contextFields returns the fields returned by the extractors for ctx followed by the
+// fields stored in ctx. The result does not share its backing array with ctx.
+func contextFields(ctx context.Context) []field {
+	stored, _ := ctx.Value(contextKey{}).([]field)
+	var extracted []Field
+	if p := extractors.Load(); p != nil {
+		for _, fn := range *p {
+			extracted = append(extracted, fn(ctx)...)
+		}
+	}
+	if len(stored) == 0 && len(extracted) == 0 {
+		return nil
+	}
+
+	merged := make([]field, 0, len(extracted)+len(stored))
+	for _, f := range extracted {
+		// The zero Field is returned by the Field method of nil fields, like Error(key, nil).
+		if f != (Field{}) {
+			merged = append(merged, f.field())
+		}
+	}
+	return append(merged, stored...)
+}
+
+// dedup returns the fields, keeping only the last field of every non-empty key.
+// It returns fields itself if no field is dropped.
+func dedup(fields []field) []field {
+	var drop []bool
+	seen := make(map[string]bool, len(fields))
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i] == nil {
+			continue
+		}
+		k := fields[i]().key
+		if k == "" {
+			continue
+		}
+		if seen[k] {
+			if drop == nil {
+				drop = make([]bool, len(fields))
+			}
+			drop[i] = true
+		}
+		seen[k] = true
+	}
+	if drop == nil {
+		return fields
+	}
+
+	out := make([]field, 0, len(fields))
+	for i, f := range fields {
+		if !drop[i] {
+			out = append(out, f)
+		}
+	}
+	return out
+}
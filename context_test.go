@@ -0,0 +1,136 @@
+package errorc
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContextWith(t *testing.T) {
+	ctx := context.Background()
+	if got := ContextWith(ctx); got != ctx {
+		t.Fatalf("ContextWith without fields returned a new context")
+	}
+	if got := ContextWith(ctx, nil); got != ctx {
+		t.Fatalf("ContextWith with nil fields returned a new context")
+	}
+
+	parent := ContextWith(ctx, String("request_id", "r1"))
+	a := ContextWith(parent, String("tenant", "a"))
+	b := ContextWith(parent, String("tenant", "b"))
+
+	base := New("not found")
+	if got, want := WithContext(a, base).Error(), "not found, request_id: r1, tenant: a"; got != want {
+		t.Errorf("a: got %q, want %q", got, want)
+	}
+	if got, want := WithContext(b, base).Error(), "not found, request_id: r1, tenant: b"; got != want {
+		t.Errorf("b: got %q, want %q", got, want)
+	}
+	if got, want := WithContext(parent, base).Error(), "not found, request_id: r1"; got != want {
+		t.Errorf("parent: got %q, want %q", got, want)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	base := New("not found")
+	ctx := ContextWith(context.Background(), String("request_id", "r1"), String("user", "u1"))
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		fields []field
+		want   string
+	}{
+		{"context only", ctx, nil, "not found, request_id: r1, user: u1"},
+		{"explicit fields after context", ctx, []field{String("key", "k")}, "not found, request_id: r1, user: u1, key: k"},
+		{"explicit field overrides context", ctx, []field{String("user", "u2"), Int("attempt", 2)},
+			"not found, request_id: r1, user: u2, attempt: 2"},
+		{"duplicate explicit keys", context.Background(), []field{Int("attempt", 1), Int("attempt", 2)}, "not found, attempt: 2"},
+		{"value-only fields are kept", ctx, []field{String("", "a"), String("", "a")}, "not found, request_id: r1, user: u1, a, a"},
+		{"nil fields", ctx, []field{nil, String("user", "u2"), nil}, "not found, request_id: r1, user: u2"},
+		{"empty context", context.Background(), nil, "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WithContext(tt.ctx, base, tt.fields...)
+			if got := err.Error(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+			if !errors.Is(err, base) {
+				t.Fatalf("errors.Is(err, base) = false")
+			}
+		})
+	}
+
+	if err := WithContext(ctx, nil, String("k", "v")); err != nil {
+		t.Fatalf("WithContext(ctx, nil) = %v, want nil", err)
+	}
+	if err := WithContext(context.Background(), base); err != base {
+		t.Fatalf("WithContext without fields = %v, want the original error", err)
+	}
+	if v, ok := Lookup(WithContext(ctx, base), "request_id"); !ok || v.Value() != "r1" {
+		t.Fatalf("Lookup(request_id) = %v, %v", v, ok)
+	}
+
+	calls := 0
+	lazy := WithContext(ctx, base, Lazy("user", func() string { calls++; return "u2" }))
+	if calls != 0 {
+		t.Fatalf("WithContext computed a lazy value")
+	}
+	if got, want := lazy.Error(), "not found, request_id: r1, user: u2"; got != want || calls != 1 {
+		t.Fatalf("got %q after %d calls, want %q after 1 call", got, calls, want)
+	}
+}
+
+// registerContextExtractor registers fn for the duration of the test.
+func registerContextExtractor(t *testing.T, fn func(context.Context) []Field) {
+	t.Helper()
+	prev := extractors.Load()
+	RegisterContextExtractor(fn)
+	t.Cleanup(func() { extractors.Store(prev) })
+}
+
+type traceKey struct{}
+
+func TestRegisterContextExtractor(t *testing.T) {
+	registerContextExtractor(t, func(ctx context.Context) []Field {
+		id, ok := ctx.Value(traceKey{}).(string)
+		if !ok {
+			return nil
+		}
+		return []Field{String("trace_id", id).Field(), String("tenant", "from-trace").Field()}
+	})
+	registerContextExtractor(t, nil)
+	registerContextExtractor(t, func(context.Context) []Field {
+		return []Field{String("service", "api").Field(), Error("cause", nil).Field(), Lazy("k", nil).Field()}
+	})
+
+	base := New("not found")
+	ctx := context.WithValue(context.Background(), traceKey{}, "t1")
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		fields []field
+		want   string
+	}{
+		{"extractors in registration order", ctx, nil, "not found, trace_id: t1, tenant: from-trace, service: api"},
+		{"extractor returning no fields", context.Background(), nil, "not found, service: api"},
+		{"context fields override extracted ones", ContextWith(ctx, String("tenant", "acme")), []field{String("key", "k")},
+			"not found, trace_id: t1, service: api, tenant: acme, key: k"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithContext(tt.ctx, base, tt.fields...).Error(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := WithContext(ctx, nil); err != nil {
+		t.Fatalf("WithContext(ctx, nil) = %v, want nil", err)
+	}
+	if f, ok := Lookup(WithContext(ctx, base), "trace_id"); !ok || f.Value() != "t1" {
+		t.Fatalf("Lookup(trace_id) = %v, %v", f, ok)
+	}
+}
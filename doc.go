@@ -59,13 +59,30 @@
 //	err := With(New("operation failed"), Error("cause", cause))
 //	// operation failed, cause: disk full
 //
-// The [Int] and [Bool] helpers provide zero-allocation conversions for
-// integers and booleans (conversion done once at creation). They follow the same
-// formatting rules as String: empty key prints only the value.
+// The [Int] and [Bool] helpers attach integers and booleans without allocating.
+// Values keep their type and are converted to text only when the message is rendered.
+// They follow the same formatting rules as String: empty key prints only the value.
 //
 //	err := With(New("query failed"), Int("retries", 3), Bool("cached", false))
 //	// query failed, retries: 3, cached: false
 //
+// Further helpers cover other common value types: [Int64], [Uint64], [Float64],
+// [Duration], [Time] and [TimeFormat], [Bytes], [Hex], [Stringer], and [Any], which
+// picks the field kind from the dynamic type of its argument.
+//
+//	err := With(New("request timed out"), Duration("timeout", 1500*time.Millisecond), Uint64("bytes", 512))
+//	// request timed out, timeout: 1.5s, bytes: 512
+//
+// [Lazy] and its typed variants [LazyInt64], [LazyFloat64], [LazyBool], and [LazyAny]
+// defer computing a value until the field is first read, for example when the error
+// message is rendered. Errors that are only matched with [errors.Is] never compute it.
+//
+//	err := With(ErrNotFound, Lazy("request", req.Dump))
+//
+// Every [Field] reports the kind of its value through Field.Kind, and typed accessors
+// such as Field.Int64 and Field.Bool return the original value, so structured
+// encoders can emit numbers as numbers.
+//
 // Structured keys are provided by the github.com/ygrebnov/keys package. The generic field
 // helpers in this package accept those keys directly because they have an
 // underlying string type. For example:
@@ -83,6 +100,101 @@
 //	err := With(New("invalid input"), String(userIDKey, "123"), String(userEmailKey, "user@example.com"))
 //	// invalid input, user.id: 123, user.email: user@example.com
 //
+// The fields attached by [With] can be read back without parsing the error message.
+// [Fields] returns the fields of the whole Unwrap chain in message order, and [Lookup]
+// returns the most recently attached field with the given key.
+//
+//	err := With(New("rate limited"), String("retry_after", "5s"))
+//	if f, ok := Lookup(err, "retry_after"); ok {
+//		// f.Value() == "5s"
+//	}
+//
+// [Append] aggregates several errors into a [Multi], which renders every error with its
+// fields and matches any of them with errors.Is and errors.As. Shared fields are attached
+// by wrapping the Multi with With:
+//
+//	err = Append(err, With(ErrRequired, String("field", "name")))
+//	err = Append(err, With(ErrTooShort, String("field", "password")))
+//	err = With(err, String("request_id", id))
+//	// 2 errors: [required, field: name; too short, field: password], request_id: 42
+//
+// [ContextWith] stores request-scoped fields, such as a request ID, on a [context.Context],
+// and [WithContext] attaches them to an error along with explicit fields, keeping the last
+// field of every key:
+//
+//	ctx = ContextWith(ctx, String("request_id", id))
+//	return WithContext(ctx, ErrNotFound, String("key", key))
+//	// not found, request_id: 42, key: k
+//
+// [RegisterContextExtractor] registers functions returning fields extracted from the
+// context, such as trace IDs stored by a tracing library, which WithContext attaches to
+// every error before the fields stored with ContextWith.
+//
+// Errors returned by [With] implement [log/slog.LogValuer]. They are logged as a group
+// holding the wrapped error message under the "msg" key and each field as its own attribute.
+// [Attrs] converts the fields of an error chain to [log/slog.Attr] values:
+//
+//	logger.Error("request failed", slog.Any("error", err))
+//	// level=ERROR msg="request failed" error.msg="invalid input" error.user_id=42
+//	logger.LogAttrs(ctx, slog.LevelError, err.Error(), Attrs(err)...)
+//	// level=ERROR msg="invalid input, user_id: 42" user_id=42
+//
+// Errors returned by [With] implement [encoding/json.Marshaler]. Each With layer is encoded
+// as an object holding the wrapped error message, the fields with their types preserved,
+// and the next error of the chain under "cause". [JSON] encodes any error and accepts
+// [WithJSONLayout] to merge the fields of all layers into a single flat object:
+//
+//	err := With(With(New("not found"), String("id", "1")), Int("attempt", 2))
+//	b, _ := json.Marshal(err)
+//	// {"message":"not found","fields":{"attempt":2},"cause":{"message":"not found","fields":{"id":"1"}}}
+//	b, _ = JSON(err, WithJSONLayout(JSONFlat))
+//	// {"message":"not found","fields":{"id":"1","attempt":2}}
+//
+// A [Renderer] configures how fields are rendered in error messages: the separators, quoting
+// of keys and values containing separators or whitespace, and escaping of control characters.
+// [SetRenderer] selects a Renderer for every error, and Renderer.With for a single With call.
+// [Logfmt] renders logfmt-style fields:
+//
+//	err := Logfmt.With(New("request failed"), String("path", "/a b"), Int("status", 503))
+//	// request failed path="/a b" status=503
+//
+// [Parse] and Renderer.Parse split a rendered message back into the message and its
+// fields, which is exact for renderers with Quote set.
+//
+// [Sensitive] and [Secret] mark fields holding personal data or credentials. Every output
+// redacts them according to its [RedactionPolicy]: the value can be rendered verbatim,
+// masked, hashed, or omitted with its key. By default, sensitive values are masked and
+// secret fields are omitted. [SetRedactionPolicy] sets the policy of an [Output], and
+// [WithJSONRedactionPolicy] the policy of a single JSON call. [Fields] and [Lookup] return
+// the original values, and Field.Redact applies a policy for other exporters:
+//
+//	err := With(ErrLoginFailed, Sensitive(String("email", email)), Secret(String("token", token)))
+//	// login failed, email: ***
+//	SetRedactionPolicy(OutputSlog, RedactionPolicy{Sensitive: RedactHash, Secret: RedactOmit})
+//
+// Stack traces are opt-in. [Stack] records the stack of a single With call, [WithStack]
+// the stack of a single New call, and [SetStackTraces] enables recording for every call.
+// [StackTrace] returns the frames recorded by the innermost With call of an error chain,
+// or by New if no With call recorded a stack.
+// Capturing a stack makes With several times slower; see BenchmarkWithStack and
+// BenchmarkWithStackTraces.
+//
+// Errors created by [New] and [With] implement [fmt.Formatter]. %v and %s print the
+// message and %q quotes it. %+v prints the message followed by each layer of the
+// Unwrap chain: the fields and recorded stack of the layer, indented, and the message
+// of each wrapped error on a "caused by: " line. %#v prints the equivalent Go call:
+//
+//	err := With(ErrNotFound, Stack(), String("id", "42"))
+//	fmt.Printf("%+v\n", err)
+//	// not found, id: 42
+//	//	id: 42
+//	//	main.load
+//	//		/app/main.go:12
+//	//	...
+//	// caused by: not found
+//	fmt.Printf("%#v\n", err)
+//	// errorc.With(errorc.New("not found"), errorc.Stack(), errorc.String("id", "42"))
+//
 // Namespaced errors can be created using [New] with [WithNamespace] or via
 // (Namespace).NewError and [ErrorFactory], for example:
 //
@@ -99,4 +211,65 @@
 //	storageErr := ErrorFactory("storage")
 //	err := storageErr("read_failed")
 //	// err.Error() == "storage: read_failed"
+//
+// Errors created under a namespace keep it as structured data. A [Namespace] can be used
+// as the target of [errors.Is], and Namespace.Contains is a shorthand for it:
+//
+//	errors.Is(err, storage) // true if err wraps an error created under "storage"
+//	storage.Contains(err)   // same
+//
+// Namespaces form a hierarchy. Namespace.Child nests a namespace, and errors created
+// under a child are members of all its ancestors. Segments are rendered separated by
+// ": " unless configured otherwise with [SetNamespaceSeparator]:
+//
+//	s3 := storage.Child("s3") // "storage/s3"
+//	err := s3.NewError("read_failed")
+//	// err.Error() == "storage: s3: read_failed"
+//	storage.Contains(err) // true
+//
+// [WithCode] gives an error a stable, machine-readable code. Errors created by New with
+// the same namespace and code match with [errors.Is] even if they are distinct values,
+// for example after being decoded from another process. [Code] returns the nearest code
+// in an error chain:
+//
+//	ErrReadFailed := storage.NewError("read failed", WithCode("read_failed"))
+//	received := New("read failed", WithNamespace("storage"), WithCode("read_failed"))
+//	errors.Is(received, ErrReadFailed) // true
+//	Code(With(received, String("key", "k1"))) // "read_failed"
+//
+// [WithDescription] and [WithMetadata] describe an error declaration without changing
+// its message; [Metadata] reads a metadata value back from an error chain.
+//
+// Errors are classified as [Retryable], [Temporary], or [Permanent] by the [WithClass]
+// option of New, by default for a namespace with [SetNamespaceClass], or for a single
+// occurrence with the [Classify] field. [IsRetryable] walks the error chain and also
+// honors the Temporary and Timeout methods of standard library errors. [RetryAfter]
+// attaches how long to wait before retrying, read back with [RetryDelay]:
+//
+//	var ErrConflict = New("conflict", WithClass(Retryable))
+//	err := With(ErrConflict, RetryAfter(time.Second))
+//	IsRetryable(err) // true
+//	RetryDelay(err)  // 1s, true
+//
+// A [Registry] is an opt-in catalog of declared errors. Namespaces bound with
+// Registry.Namespace record every error created under them, which can then be listed
+// with Registry.Entries or looked up by code. Duplicate codes panic at initialization
+// unless [OnDuplicate] is used to report them instead. Errors reconstructed from another
+// process are created with [Unrecorded] so they are not recorded:
+//
+//	var catalog = NewRegistry()
+//	var storage = catalog.Namespace("storage")
+//	var ErrReadFailed = storage.NewError("read failed",
+//		WithCode("read_failed"), WithDescription("The object could not be read."))
+//
+//	for _, e := range catalog.Entries() {
+//		fmt.Println(e.Namespace, e.Code, e.Description)
+//	}
+//
+// [SetRenderer], [SetStackTraces], [SetNamespaceSeparator], [SetRedactionPolicy],
+// [SetNamespaceClass], and [RegisterContextExtractor] change process-wide settings.
+// They are safe to call concurrently, but they affect every package using errorc in the
+// same binary, including third-party libraries. Applications should call them once at
+// program start; libraries should leave them alone, except for SetNamespaceClass and
+// RegisterContextExtractor on namespaces and context keys they own.
 package errorc
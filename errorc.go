@@ -1,8 +1,11 @@
 package errorc
 
 import (
-	"errors"
-	"strconv"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
 	"unsafe"
 )
 
@@ -11,58 +14,124 @@ import (
 // and ErrorFactory.
 type Namespace string
 
-// NewError creates a new error with the given message under this namespace.
+// NewError creates a new error with the given message and options under this namespace.
 // If message is empty and the namespace is non-empty, the resulting error string
 // contains only the namespace prefix, for example "storage: ".
-func (n Namespace) NewError(message string) error {
-	return New(message, WithNamespace(n))
+func (n Namespace) NewError(message string, opts ...Option) error {
+	return newSentinel(4, message, append([]Option{WithNamespace(n)}, opts...))
 }
 
-// Option defines a function that modifies the byte representation of an identifier.
-// It is used when constructing namespaced errors (New, Namespace.NewError,
-// and ErrorFactory).
-type Option func([]byte) []byte
+// Option configures an error created by New, Namespace.NewError, or ErrorFactory.
+type Option func(*options)
+
+// options holds the configuration collected from Option values.
+type options struct {
+	ns          Namespace
+	code        string
+	description string
+	metadata    map[string]any
+	stack       bool
+	unrecorded  bool
+	class       Class
+}
 
 // WithNamespace sets a namespace prefix for an identifier. Namespace and identifier are separated by a colon.
+// Applying WithNamespace several times nests the namespaces: each one becomes the parent of the
+// namespace set before it, as if created with Namespace.Child.
 func WithNamespace(ns Namespace) Option {
-	return func(b []byte) []byte {
-		// Store the namespace bytes at the front; WithNamespace is responsible for
-		// inserting the ": " separator between namespace and message.
-		if len(ns) == 0 {
-			return b
+	return func(o *options) {
+		switch {
+		case len(ns) == 0:
+			return
+		case len(o.ns) == 0:
+			o.ns = ns
+		default:
+			o.ns = join(ns.path(), o.ns.path())
 		}
-		prefix := make([]byte, 0, len(ns)+len(b)+2)
-		prefix = append(prefix, []byte(ns)...)
-		prefix = append(prefix, ':')
-		prefix = append(prefix, ' ')
-		prefix = append(prefix, b...)
-		return prefix
+	}
+}
+
+// WithStack records the stack of the New call, regardless of SetStackTraces.
+// The stack can be retrieved with StackTrace.
+func WithStack() Option {
+	return func(o *options) {
+		o.stack = true
 	}
 }
 
 // New creates a new error from the given message and options.
 //
-// Options can prepend a namespace or other components to form identifiers like
-// "storage: read_failed". If a prefix is provided, it is expected to include its
-// own separator (for example, WithNamespace adds ": ") and the message is appended
-// directly when non-empty. If both the prefix and message are empty, New returns errors.New("").
+// Options can prepend a namespace to form identifiers like "storage: read_failed".
+// Namespace and message are separated by ": ", and the message is appended directly
+// when non-empty. If both the namespace and message are empty, the error message is empty.
+// Segments of a hierarchical namespace are separated as configured by SetNamespaceSeparator,
+// by default with ": ", for example "storage: s3: read_failed".
+//
+// If a code is set with WithCode and message is empty, the code is used as the message.
 func New(message string, opts ...Option) error {
-	// Start with an empty buffer for prefix.
-	b := make([]byte, 0, len(message))
+	return newSentinel(4, message, opts)
+}
+
+// newSentinel implements New, Namespace.NewError, and ErrorFactory. The stack is recorded
+// with callers(skip); every entry point calls newSentinel directly and passes 4, skipping
+// runtime.Callers, callers, newSentinel, and the entry point itself.
+func newSentinel(skip int, message string, opts []Option) error {
+	var o options
 	for _, opt := range opts {
-		b = opt(b)
+		opt(&o)
+	}
+	if message == "" {
+		message = o.code
+	}
+
+	e := &sentinel{msg: message, ns: o.ns, path: o.ns.path(), code: o.code, metadata: o.metadata, class: o.class}
+	if o.stack || stackTraces.Load() {
+		e.stack = callers(skip)
+	}
+	e.s = e.render(defaultNamespaceSeparator)
+	if r := registryOf(e.ns); r != nil && !o.unrecorded {
+		r.add(e, o.description)
 	}
+	return e
+}
+
+// sentinel is the error returned by New.
+type sentinel struct {
+	s        string    // rendered message
+	msg      string    // message without the namespace prefix
+	ns       Namespace // path joined by "/"
+	path     []string  // segments of ns, outermost first
+	code     string
+	metadata map[string]any
+	stack    []uintptr
+	class    Class
+}
 
-	// Append the base message directly; any separator should be provided by options.
-	if len(message) > 0 {
-		b = append(b, message...)
+func (e *sentinel) Error() string {
+	if sep := namespaceSeparator.Load(); sep != nil && len(e.path) > 1 {
+		return e.render(*sep)
 	}
+	return e.s
+}
 
-	if len(b) == 0 {
-		return errors.New("")
+// render builds the error message, separating namespace segments with sep.
+func (e *sentinel) render(sep string) string {
+	if e.ns == "" {
+		return e.msg
 	}
+
+	// The message is usually short, so estimate the buffer size from the namespace and message.
+	b := make([]byte, 0, len(e.ns)+len(e.msg)+2)
+	for i, segment := range e.path {
+		if i > 0 {
+			b = append(b, sep...)
+		}
+		b = append(b, segment...)
+	}
+	b = append(b, ':', ' ')
+	b = append(b, e.msg...)
 	// b is not mutated after this point; unsafe.String avoids an extra allocation.
-	return errors.New(unsafe.String(&b[0], len(b)))
+	return unsafe.String(&b[0], len(b))
 }
 
 // ErrorFactory returns a function that creates errors under the given namespace.
@@ -71,7 +140,7 @@ func New(message string, opts ...Option) error {
 // prefix, for example "storage: ".
 func ErrorFactory(ns Namespace) func(message string) error {
 	return func(message string) error {
-		return New(message, WithNamespace(ns))
+		return newSentinel(4, message, []Option{WithNamespace(ns)})
 	}
 }
 
@@ -80,10 +149,43 @@ func ErrorFactory(ns Namespace) func(message string) error {
 // If no non-nil fields are provided, it simply returns the original error.
 // Unwrapping this error will yield the original error.
 func With(err error, fields ...field) error {
-	if err == nil {
-		return nil
+	// With is kept small enough to be inlined, so that the error does not escape when the
+	// caller discards it. Renderer.With and WithContext follow the same pattern.
+	e := &errorWithFields{e: err}
+	if !e.add(fields) {
+		return err
 	}
+	return e
+}
+
+// withCallers returns the stack of the caller of the entry point calling errorWithFields.add,
+// skipping runtime.Callers, callers, withCallers, errorWithFields.add, and the entry point.
+//
+//go:noinline
+func withCallers() []uintptr {
+	return callers(5)
+}
 
+type errorWithFields struct {
+	e     error
+	f     []field
+	r     *Renderer // set by Renderer.With
+	stack []uintptr // set by a Stack field or SetStackTraces
+	class Class     // set by a Classify field
+}
+
+// add appends the non-nil fields to e.f and moves the data of the markers, such as the
+// stack recorded by Stack and the class set by Classify, to the members of e. If
+// SetStackTraces is enabled and no Stack field is given, it records the stack. It reports
+// whether any field was added, and false if e wraps a nil error.
+// It must be called directly by With, Renderer.With, WithContext, or WithFields, so that
+// the stack starts at their caller, and is not inlined into them, so that they stay cheap.
+//
+//go:noinline
+func (e *errorWithFields) add(fields []field) bool {
+	if e.e == nil {
+		return false
+	}
 	n := 0
 	for _, f := range fields {
 		if f != nil {
@@ -91,38 +193,52 @@ func With(err error, fields ...field) error {
 		}
 	}
 	if n == 0 {
-		return err
-	}
-
-	e := &errorWithFields{
-		e: err,
-		f: make([]field, 0, n),
+		return false
 	}
 
 	for _, f := range fields {
-		if f != nil {
+		if f == nil {
+			continue
+		}
+		// Calling f does not compute the value of a lazy field, see Field.resolve.
+		switch sf := f(); sf.kind {
+		case kindStack:
+			e.stack = sf.any.([]uintptr)
+		case kindClass:
+			e.class = Class(sf.num)
+		default:
+			if e.f == nil {
+				e.f = make([]field, 0, n)
+			}
 			e.f = append(e.f, f)
 		}
 	}
-
-	return e
-}
-
-type errorWithFields struct {
-	e error
-	f []field
+	if e.stack == nil && stackTraces.Load() {
+		e.stack = withCallers()
+	}
+	return true
 }
 
 func (e *errorWithFields) Error() string {
 	// Since With returns nil if err is nil, e.e cannot be nil.
 	b := []byte(e.e.Error())
+	if r := e.renderer(); r != nil {
+		return r.render(b, e.f)
+	}
 	for _, f := range e.f {
+		sf := f.get()
+		sf, ok := sf.redactFor(OutputText)
+		if !ok {
+			continue
+		}
 		b = append(b, ',')
 		b = append(b, ' ')
-		sf := f()
-		b = append(b, sf.getBytes()...)
+		b = sf.appendBytes(b)
+	}
+	// b is empty only if the message is empty and every field is omitted.
+	if len(b) == 0 {
+		return ""
 	}
-	// At this point, b is non-empty.
 	return unsafe.String(&b[0], len(b))
 }
 
@@ -131,7 +247,38 @@ func (e *errorWithFields) Unwrap() error {
 	return e.e
 }
 
-type field func() kv
+// fields appends the fields of this layer to dst.
+func (e *errorWithFields) fields(dst []Field) []Field {
+	for _, f := range e.f {
+		dst = append(dst, f.get())
+	}
+	return dst
+}
+
+type field func() Field
+
+// get returns the Field created by f, computing the value of a lazy field.
+func (f field) get() Field {
+	return f().resolve()
+}
+
+// Field returns the Field created by a field helper, for APIs taking Field values, such as
+// the extractors registered with RegisterContextExtractor:
+//
+//	return []Field{String("trace_id", id).Field()}
+//
+// The value of a lazy field is computed. A nil field, like the one returned by Lazy with
+// a nil function, and a field created by Stack or Classify return the zero Field, an empty
+// value without a key.
+func (f field) Field() Field {
+	if f == nil {
+		return Field{}
+	}
+	if sf := f(); !sf.marker() {
+		return sf.resolve()
+	}
+	return Field{}
+}
 
 // String creates a new field with the given key and value.
 // The key can be any type whose underlying type is string (constraint ~string),
@@ -139,68 +286,191 @@ type field func() kv
 func String[K ~string](key K, value string) field {
 	// Convert once here so the closure doesn't need to repeatedly convert.
 	ks := string(key)
-	return func() kv {
-		return kv{
-			key:   ks,
-			value: value,
+	return func() Field {
+		return Field{
+			key:  ks,
+			kind: KindString,
+			str:  value,
 		}
 	}
 }
 
-// Int creates a field whose value is the decimal representation of an int.
-// The conversion happens at creation time to avoid repeated work when the closure is invoked.
+// Int creates a field holding an int. The value keeps its type and is rendered
+// in decimal representation when the error message is built.
 func Int[K ~string](key K, value int) field {
 	ks := string(key)
-	vs := strconv.Itoa(value)
-	return func() kv {
-		return kv{key: ks, value: vs}
+	return func() Field {
+		return Field{key: ks, kind: KindInt64, num: uint64(value)}
 	}
 }
 
-// Bool creates a field whose value is the string representation of a bool ("true" / "false").
-// The conversion happens at creation time to avoid repeated work when the closure is invoked.
+// Bool creates a field holding a bool. The value keeps its type and is rendered
+// as "true" / "false" when the error message is built.
 func Bool[K ~string](key K, value bool) field {
 	ks := string(key)
-	vs := strconv.FormatBool(value)
-	return func() kv {
-		return kv{key: ks, value: vs}
+	var n uint64
+	if value {
+		n = 1
+	}
+	return func() Field {
+		return Field{key: ks, kind: KindBool, num: n}
 	}
 }
 
 // Error creates a field from an error value. If err is nil it returns nil so that
-// it will be ignored by With(). The error's message is captured at field creation time.
+// it will be ignored by With(). The error's message is captured at field creation time; like in fmt,
+// it is "<nil>" if err is a nil pointer whose Error method panics.
 // This mirrors String's formatting rules: if key is empty only the value is printed.
 func Error[K ~string](key K, err error) field {
 	if err == nil {
 		return nil
 	}
 	ks := string(key)
-	msg := err.Error() // capture now; avoids calling Error repeatedly if closure evaluated multiple times
-	return func() kv {
-		return kv{
-			key:   ks,
-			value: msg,
+	msg := nilSafe(err.Error, err) // capture now; avoids calling Error repeatedly if closure evaluated multiple times
+	return func() Field {
+		return Field{
+			key:  ks,
+			kind: KindError,
+			str:  msg,
+			any:  err,
 		}
 	}
 }
 
-// kv contains a key-value pair for additional context in an error.
-type kv struct {
-	value, key string
+// Int64 creates a field holding an int64.
+// It follows String's formatting rules: if key is empty only the value is printed.
+func Int64[K ~string](key K, value int64) field {
+	ks := string(key)
+	return func() Field {
+		return Field{key: ks, kind: KindInt64, num: uint64(value)}
+	}
+}
+
+// Uint64 creates a field holding a uint64.
+// It follows String's formatting rules: if key is empty only the value is printed.
+func Uint64[K ~string](key K, value uint64) field {
+	ks := string(key)
+	return func() Field {
+		return Field{key: ks, kind: KindUint64, num: value}
+	}
+}
+
+// Float64 creates a field holding a float64. The value is rendered in the shortest
+// representation that round-trips, like strconv.FormatFloat(value, 'g', -1, 64).
+func Float64[K ~string](key K, value float64) field {
+	ks := string(key)
+	n := math.Float64bits(value)
+	return func() Field {
+		return Field{key: ks, kind: KindFloat64, num: n}
+	}
+}
+
+// Duration creates a field holding a time.Duration, rendered like time.Duration.String, for example "1.5s".
+func Duration[K ~string](key K, value time.Duration) field {
+	ks := string(key)
+	return func() Field {
+		return Field{key: ks, kind: KindDuration, num: uint64(value)}
+	}
+}
+
+// Time creates a field holding a time.Time, rendered using the time.RFC3339Nano layout.
+func Time[K ~string](key K, value time.Time) field {
+	return TimeFormat(key, value, time.RFC3339Nano)
+}
+
+// TimeFormat creates a field holding a time.Time, rendered using the given layout
+// as defined by time.Time.Format.
+func TimeFormat[K ~string](key K, value time.Time, layout string) field {
+	ks := string(key)
+	return func() Field {
+		return Field{key: ks, kind: KindTime, str: layout, any: value}
+	}
+}
+
+// Bytes creates a field whose value is the given bytes interpreted as a string.
+// The bytes are copied at creation time, so the caller may reuse the slice.
+func Bytes[K ~string](key K, value []byte) field {
+	return String(key, string(value))
+}
+
+// Hex creates a field whose value is the hexadecimal encoding of the given bytes.
+// The encoding happens at creation time, so the caller may reuse the slice.
+func Hex[K ~string](key K, value []byte) field {
+	return String(key, hex.EncodeToString(value))
 }
 
-func (s *kv) getBytes() []byte {
-	switch {
-	case s.key == "" && s.value == "":
+// Stringer creates a field from a fmt.Stringer. If value is nil it returns nil so that
+// it will be ignored by With(). Like Error, the string is captured at field creation time, and is
+// "<nil>" if value is a nil pointer whose String method panics.
+func Stringer[K ~string](key K, value fmt.Stringer) field {
+	if value == nil {
 		return nil
-	case s.key == "":
-		return []byte(s.value)
+	}
+	return String(key, nilSafe(value.String, value))
+}
+
+// nilSafe returns the result of fn, a method of v. If fn panics and v is a nil pointer, it
+// returns "<nil>", like fmt does; other panics are propagated.
+func nilSafe(fn func() string, v any) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+				s = "<nil>"
+				return
+			}
+			panic(r)
+		}
+	}()
+	return fn()
+}
+
+// Any creates a field from an arbitrary value. The field kind is chosen from the
+// dynamic type of value: strings, signed and unsigned integers, floats, bools,
+// time.Duration, time.Time, errors and fmt.Stringer values, including named types
+// based on them, produce the same fields as the dedicated constructors. Any other
+// value is kept as is with KindAny and rendered using fmt.Sprint.
+func Any[K ~string](key K, value any) field {
+	switch v := value.(type) {
+	case string:
+		return String(key, v)
+	case int:
+		return Int(key, v)
+	case int64:
+		return Int64(key, v)
+	case uint64:
+		return Uint64(key, v)
+	case float64:
+		return Float64(key, v)
+	case bool:
+		return Bool(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case time.Time:
+		return Time(key, v)
+	case []byte:
+		return Bytes(key, v)
+	case error:
+		return Error(key, v)
+	case fmt.Stringer:
+		return Stringer(key, v)
 	}
 
-	b := []byte(s.key)
-	b = append(b, ':')
-	b = append(b, ' ')
-	b = append(b, s.value...)
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return String(key, rv.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Int64(key, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return Uint64(key, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return Float64(key, rv.Float())
+	case reflect.Bool:
+		return Bool(key, rv.Bool())
+	}
 
-	return b
+	ks := string(key)
+	return func() Field {
+		return Field{key: ks, kind: KindAny, any: value}
+	}
 }
@@ -2,7 +2,9 @@ package errorc
 
 import (
 	"errors"
+	"net/url"
 	"testing"
+	"time"
 )
 
 type typedError struct{ m string }
@@ -230,3 +232,101 @@ func TestNamespace_NewError(t *testing.T) {
 		t.Fatalf("Namespace.NewError() = %q, want %q", got, want)
 	}
 }
+
+type stringerType struct{ s string }
+
+func (s stringerType) String() string { return s.s }
+
+func TestTypedConstructors(t *testing.T) {
+	ts := time.Date(2025, 6, 20, 10, 30, 0, 500, time.UTC)
+
+	tests := []struct {
+		name  string
+		field field
+		kind  Kind
+		want  string
+	}{
+		{"Int64", Int64("n", -9000000000), KindInt64, "base, n: -9000000000"},
+		{"Int64 empty key", Int64("", 1), KindInt64, "base, 1"},
+		{"Uint64", Uint64("bytes", 18446744073709551615), KindUint64, "base, bytes: 18446744073709551615"},
+		{"Float64", Float64("ratio", 0.25), KindFloat64, "base, ratio: 0.25"},
+		{"Float64 large", Float64("", 1e21), KindFloat64, "base, 1e+21"},
+		{"Duration", Duration("timeout", 1500*time.Millisecond), KindDuration, "base, timeout: 1.5s"},
+		{"Time", Time("at", ts), KindTime, "base, at: 2025-06-20T10:30:00.0000005Z"},
+		{"TimeFormat", TimeFormat("day", ts, time.DateOnly), KindTime, "base, day: 2025-06-20"},
+		{"Bytes", Bytes("body", []byte("raw")), KindString, "base, body: raw"},
+		{"Bytes empty", Bytes("", nil), KindString, "base, "},
+		{"Hex", Hex("digest", []byte{0xde, 0xad, 0xbe, 0xef}), KindString, "base, digest: deadbeef"},
+		{"Stringer", Stringer("s", stringerType{"str"}), KindString, "base, s: str"},
+		{"Stringer nil pointer", Stringer("s", (*url.URL)(nil)), KindString, "base, s: <nil>"},
+		{"Error nil pointer", Error("e", (*typedError)(nil)), KindError, "base, e: <nil>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := With(New("base"), tt.field)
+			if got := err.Error(); got != tt.want {
+				t.Fatalf("Error() = %q, want %q", got, tt.want)
+			}
+			if got := Fields(err)[0].Kind(); got != tt.kind {
+				t.Fatalf("Kind() = %s, want %s", got, tt.kind)
+			}
+		})
+	}
+
+	if Stringer("s", nil) != nil {
+		t.Fatalf("Stringer(nil) should return nil")
+	}
+}
+
+func TestBytes_copiesInput(t *testing.T) {
+	b := []byte("abc")
+	bytesErr := With(New("base"), Bytes("b", b), Hex("h", b))
+	b[0] = 'x'
+	if got := bytesErr.Error(); got != "base, b: abc, h: 616263" {
+		t.Fatalf("Error() = %q, want 'base, b: abc, h: 616263'", got)
+	}
+}
+
+func TestAny(t *testing.T) {
+	type status int
+	type label string
+	cause := errors.New("eof")
+
+	tests := []struct {
+		name  string
+		value any
+		kind  Kind
+		want  string
+	}{
+		{"string", "v", KindString, "base, k: v"},
+		{"int", 3, KindInt64, "base, k: 3"},
+		{"int8", int8(-3), KindInt64, "base, k: -3"},
+		{"uint16", uint16(7), KindUint64, "base, k: 7"},
+		{"float64", 1.5, KindFloat64, "base, k: 1.5"},
+		{"bool", true, KindBool, "base, k: true"},
+		{"duration", time.Second, KindDuration, "base, k: 1s"},
+		{"time", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), KindTime, "base, k: 2025-01-02T00:00:00Z"},
+		{"bytes", []byte("raw"), KindString, "base, k: raw"},
+		{"error", cause, KindError, "base, k: eof"},
+		{"stringer", stringerType{"str"}, KindString, "base, k: str"},
+		{"named int", status(404), KindInt64, "base, k: 404"},
+		{"named string", label("x"), KindString, "base, k: x"},
+		{"slice", []int{1, 2}, KindAny, "base, k: [1 2]"},
+		{"nil", nil, KindAny, "base, k: <nil>"},
+		{"nil pointer stringer", (*url.URL)(nil), KindString, "base, k: <nil>"},
+		{"nil pointer error", (*typedError)(nil), KindError, "base, k: <nil>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := With(New("base"), Any("k", tt.value))
+			if got := err.Error(); got != tt.want {
+				t.Fatalf("Error() = %q, want %q", got, tt.want)
+			}
+			if got := Fields(err)[0].Kind(); got != tt.kind {
+				t.Fatalf("Kind() = %s, want %s", got, tt.kind)
+			}
+		})
+	}
+}
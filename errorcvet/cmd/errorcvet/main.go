@@ -0,0 +1,22 @@
+// Command errorcvet reports misuse of the github.com/ygrebnov/errorc package.
+//
+// Usage:
+//
+//	errorcvet [-constkeys] [-keypattern regexp] [packages]
+//
+// It can also be run by go vet:
+//
+//	go vet -vettool=$(which errorcvet) ./...
+//
+// See the errorcvet package for the list of reported patterns.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/ygrebnov/errorc/errorcvet"
+)
+
+func main() {
+	singlechecker.Main(errorcvet.Analyzer)
+}
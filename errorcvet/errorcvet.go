@@ -0,0 +1,298 @@
+// Package errorcvet defines an analyzer reporting misuse of the
+// github.com/ygrebnov/errorc package.
+//
+// The analyzer reports:
+//   - field helpers, such as errorc.String, called with an empty constant key and an
+//     empty constant value: the field renders as an empty ", " suffix;
+//   - several fields with the same key passed to a single errorc.With or Renderer.With call;
+//   - errorc.With calls wrapping the result of another errorc.With call, which can be
+//     merged into a single call, and likewise for Renderer.With;
+//   - errorc.New, Namespace.NewError, and errorc.ErrorFactory called inside a function:
+//     every call creates a distinct error, so errors.Is cannot match it against another
+//     call's result. Errors reconstructed with both errorc.WithCode and errorc.Unrecorded,
+//     calls in init functions, and calls in test files are not reported. errorc.WithCode
+//     alone is not enough: a second call panics if the namespace is bound to an
+//     errorc.Registry;
+//   - with the -constkeys flag, field keys that are neither constants nor package-level
+//     variables, for code bases where every key is declared in advance;
+//   - constant keys that do not match the regular expression set with the -keypattern flag.
+//
+// Keys that are package-level variables, like structured keys created with
+// github.com/ygrebnov/keys, are compared by identity when looking for duplicates.
+package errorcvet
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const errorcPath = "github.com/ygrebnov/errorc"
+
+// Analyzer reports misuse of the errorc package.
+var Analyzer = &analysis.Analyzer{
+	Name:     "errorcvet",
+	Doc:      "report misuse of the github.com/ygrebnov/errorc package",
+	URL:      "https://pkg.go.dev/github.com/ygrebnov/errorc/errorcvet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var (
+	// keyPattern is the value of the -keypattern flag.
+	keyPattern string
+	// constKeys is the value of the -constkeys flag.
+	constKeys bool
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&keyPattern, "keypattern", "",
+		"regular expression non-empty constant field keys must match, for example ^[a-z][a-z0-9_.]*$ (default: any key)")
+	Analyzer.Flags.BoolVar(&constKeys, "constkeys", false,
+		"report field keys that are neither constants nor package-level variables")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	// The errorc package itself builds fields from variables.
+	if pass.Pkg.Path() == errorcPath {
+		return nil, nil
+	}
+
+	var pattern *regexp.Regexp
+	if keyPattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(keyPattern); err != nil {
+			return nil, fmt.Errorf("invalid -keypattern: %w", err)
+		}
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{(*ast.File)(nil), (*ast.FuncDecl)(nil), (*ast.FuncLit)(nil), (*ast.CallExpr)(nil)}
+
+	var (
+		inTest bool
+		// funcs holds the enclosing function declarations and literals.
+		funcs []ast.Node
+	)
+	inspect.Nodes(nodes, func(n ast.Node, push bool) bool {
+		switch n := n.(type) {
+		case *ast.File:
+			inTest = strings.HasSuffix(pass.Fset.File(n.Pos()).Name(), "_test.go")
+		case *ast.FuncDecl, *ast.FuncLit:
+			if push {
+				funcs = append(funcs, n)
+			} else {
+				funcs = funcs[:len(funcs)-1]
+			}
+		case *ast.CallExpr:
+			if !push {
+				return true
+			}
+			fn, ok := typeutil.Callee(pass.TypesInfo, n).(*types.Func)
+			if !ok || fn.Pkg() == nil || fn.Pkg().Path() != errorcPath {
+				return true
+			}
+			switch {
+			case isFieldHelper(fn):
+				checkField(pass, n, fn, pattern)
+			case isWith(fn):
+				checkWith(pass, n)
+			case isConstructor(fn):
+				if len(funcs) > 0 && !inTest && !isInit(funcs[0]) && !reconstructs(pass, n) {
+					pass.ReportRangef(n, "%s called inside a function creates a distinct error on every call; "+
+						"declare it as a package-level variable, or pass errorc.WithCode and errorc.Unrecorded "+
+						"to reconstruct a declared error", calleeName(fn))
+				}
+			}
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// checkField checks the key of a call to a field helper.
+func checkField(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func, pattern *regexp.Regexp) {
+	key := call.Args[0]
+	tv := pass.TypesInfo.Types[key]
+	if tv.Value == nil {
+		if constKeys && !isPackageVar(pass, key) {
+			pass.ReportRangef(key, "errorc.%s key is not a constant or a package-level variable", fn.Name())
+		}
+		return
+	}
+
+	k := constant.StringVal(tv.Value)
+	if k == "" {
+		if len(call.Args) > 1 && isEmptyString(pass, call.Args[1]) {
+			pass.ReportRangef(call, "errorc.%s with an empty key and an empty value renders as an empty field", fn.Name())
+		}
+		return
+	}
+	if pattern != nil && !pattern.MatchString(k) {
+		pass.ReportRangef(key, "errorc.%s key %q does not match the naming scheme %s", fn.Name(), k, pattern)
+	}
+}
+
+// checkWith reports nested With calls and duplicate keys in a call to With.
+func checkWith(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) == 0 {
+		return
+	}
+	if inner, ok := ast.Unparen(call.Args[0]).(*ast.CallExpr); ok {
+		fn, ok := typeutil.Callee(pass.TypesInfo, inner).(*types.Func)
+		if ok && fn.Pkg() != nil && fn.Pkg().Path() == errorcPath && isWith(fn) {
+			pass.ReportRangef(call, "errorc.With wraps the result of another errorc.With call; pass all fields to a single call")
+		}
+	}
+	if call.Ellipsis.IsValid() {
+		return
+	}
+
+	seen := make(map[any]bool)
+	for _, arg := range call.Args[1:] {
+		fc, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		fn, ok := typeutil.Callee(pass.TypesInfo, fc).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != errorcPath || !isFieldHelper(fn) {
+			continue
+		}
+		id, name := keyIdentity(pass, fc.Args[0])
+		if id == nil {
+			continue
+		}
+		if seen[id] {
+			pass.ReportRangef(fc.Args[0], "duplicate key %s in errorc.With call", name)
+		}
+		seen[id] = true
+	}
+}
+
+// keyIdentity returns a value identifying a key for duplicate detection, and its
+// description, or nil if the key cannot be identified statically. Empty keys are
+// not identified: several value-only fields are valid.
+func keyIdentity(pass *analysis.Pass, key ast.Expr) (any, string) {
+	if tv := pass.TypesInfo.Types[key]; tv.Value != nil {
+		if k := constant.StringVal(tv.Value); k != "" {
+			return k, fmt.Sprintf("%q", k)
+		}
+		return nil, ""
+	}
+	if obj := packageVar(pass, key); obj != nil {
+		return obj, obj.Name()
+	}
+	return nil, ""
+}
+
+// isFieldHelper reports whether fn is a field helper: a generic errorc function taking
+// a key as its first parameter and returning a field.
+func isFieldHelper(fn *types.Func) bool {
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() != nil || sig.TypeParams().Len() == 0 || sig.Params().Len() == 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	named, ok := sig.Results().At(0).Type().(*types.Named)
+	return ok && named.Obj().Name() == "field"
+}
+
+// isWith reports whether fn is errorc.With or Renderer.With.
+func isWith(fn *types.Func) bool {
+	if fn.Name() != "With" {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return true
+	}
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == "Renderer"
+}
+
+// isConstructor reports whether fn creates errors: New, ErrorFactory, or Namespace.NewError.
+func isConstructor(fn *types.Func) bool {
+	sig := fn.Type().(*types.Signature)
+	if recv := sig.Recv(); recv != nil {
+		named, ok := recv.Type().(*types.Named)
+		return ok && named.Obj().Name() == "Namespace" && fn.Name() == "NewError"
+	}
+	return fn.Name() == "New" || fn.Name() == "ErrorFactory"
+}
+
+func calleeName(fn *types.Func) string {
+	if fn.Type().(*types.Signature).Recv() != nil {
+		return "Namespace." + fn.Name()
+	}
+	return "errorc." + fn.Name()
+}
+
+// reconstructs reports whether a call to an error constructor passes both errorc.WithCode
+// and errorc.Unrecorded, reconstructing a declared error without recording it in a Registry.
+func reconstructs(pass *analysis.Pass, call *ast.CallExpr) bool {
+	var code, unrecorded bool
+	for _, arg := range call.Args {
+		c, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		fn, ok := typeutil.Callee(pass.TypesInfo, c).(*types.Func)
+		if !ok {
+			continue
+		}
+		code = code || isErrorcFunc(fn, "WithCode")
+		unrecorded = unrecorded || isErrorcFunc(fn, "Unrecorded")
+	}
+	return code && unrecorded
+}
+
+func isErrorcFunc(fn *types.Func, name string) bool {
+	return fn.Pkg() != nil && fn.Pkg().Path() == errorcPath && fn.Name() == name && isPackageFunc(fn)
+}
+
+func isPackageFunc(fn *types.Func) bool {
+	return fn.Type().(*types.Signature).Recv() == nil
+}
+
+func isInit(n ast.Node) bool {
+	d, ok := n.(*ast.FuncDecl)
+	return ok && d.Recv == nil && d.Name.Name == "init"
+}
+
+func isEmptyString(pass *analysis.Pass, e ast.Expr) bool {
+	tv := pass.TypesInfo.Types[e]
+	return tv.Value != nil && tv.Value.Kind() == constant.String && constant.StringVal(tv.Value) == ""
+}
+
+func isPackageVar(pass *analysis.Pass, e ast.Expr) bool {
+	return packageVar(pass, e) != nil
+}
+
+// packageVar returns the package-level variable e refers to, or nil.
+func packageVar(pass *analysis.Pass, e ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return nil
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.IsField() || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return nil
+	}
+	return v
+}
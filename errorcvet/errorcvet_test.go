@@ -0,0 +1,33 @@
+package errorcvet_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ygrebnov/errorc/errorcvet"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), errorcvet.Analyzer, "a")
+}
+
+func TestAnalyzer_keyPattern(t *testing.T) {
+	setFlag(t, "keypattern", `^[a-z][a-z0-9_.]*$`)
+	analysistest.Run(t, analysistest.TestData(), errorcvet.Analyzer, "naming")
+}
+
+func TestAnalyzer_constKeys(t *testing.T) {
+	setFlag(t, "constkeys", "true")
+	analysistest.Run(t, analysistest.TestData(), errorcvet.Analyzer, "constkeys")
+}
+
+func setFlag(t *testing.T, name, value string) {
+	t.Helper()
+	f := errorcvet.Analyzer.Flags.Lookup(name)
+	prev := f.Value.String()
+	if err := f.Value.Set(value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = f.Value.Set(prev) })
+}
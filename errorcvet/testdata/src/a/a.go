@@ -0,0 +1,86 @@
+package a
+
+import (
+	"keys"
+
+	"github.com/ygrebnov/errorc"
+)
+
+type Key string
+
+const requestID Key = "request_id"
+
+var (
+	ErrNotFound = errorc.New("not found")
+	storage     = errorc.Namespace("storage")
+	ErrRead     = storage.NewError("read failed")
+	newErr      = errorc.ErrorFactory("storage")
+
+	orderID = Key("order_id")
+)
+
+func init() {
+	ErrNotFound = errorc.New("not found")
+}
+
+func emptyField(err error) error {
+	return errorc.With(err, errorc.String("", "")) // want `errorc.String with an empty key and an empty value renders as an empty field`
+}
+
+func valueOnly(err error) error {
+	return errorc.With(err, errorc.String("", "value"), errorc.Int("", 1), errorc.String("", "other"))
+}
+
+func duplicates(err error) error {
+	return errorc.With(err,
+		errorc.String("id", "1"),
+		errorc.Int("id", 2), // want `duplicate key "id" in errorc.With call`
+		errorc.String(requestID, "r1"),
+		errorc.String("request_id", "r2"), // want `duplicate key "request_id" in errorc.With call`
+		errorc.String(orderID, "o1"),
+		errorc.String(orderID, "o2"), // want `duplicate key orderID in errorc.With call`
+		errorc.String(keys.UserID, "u1"),
+		errorc.String(keys.UserID, "u2"), // want `duplicate key UserID in errorc.With call`
+		errorc.Stack(),
+		errorc.Stack(),
+	)
+}
+
+func nested(err error) error {
+	return errorc.With(errorc.With(err, errorc.String("a", "1")), errorc.String("b", "2")) // want `errorc.With wraps the result of another errorc.With call; pass all fields to a single call`
+}
+
+func renderer(err error) error {
+	return errorc.Logfmt.With(errorc.Logfmt.With(err, errorc.String("a", "1")), // want `errorc.With wraps the result of another errorc.With call`
+		errorc.String("b", "2"),
+		errorc.String("b", "3"), // want `duplicate key "b" in errorc.With call`
+	)
+}
+
+func sequential(err error) error {
+	err = errorc.With(err, errorc.String("a", "1"))
+	return errorc.With(err, errorc.String("b", "2"))
+}
+
+func nonConstant(err error, key string) error {
+	local := Key("local")
+	return errorc.With(err,
+		errorc.String(key, "v"), // reported only with -constkeys
+		errorc.Int(local, 1),
+		errorc.Lazy(Key("lazy"), nil),
+	)
+}
+
+func constructors() error {
+	_ = errorc.New("created per call")       // want `errorc.New called inside a function creates a distinct error on every call`
+	_ = storage.NewError("created per call") // want `Namespace.NewError called inside a function creates a distinct error on every call`
+	_ = errorc.ErrorFactory("storage")       // want `errorc.ErrorFactory called inside a function creates a distinct error on every call`
+	_ = errorc.New("decoded", errorc.WithNamespace("storage"), errorc.WithCode("read_failed"), errorc.Unrecorded())
+	_ = errorc.New("coded", errorc.WithCode("read_failed")) // want `errorc.New called inside a function creates a distinct error on every call; declare it as a package-level variable, or pass errorc.WithCode and errorc.Unrecorded to reconstruct a declared error`
+	_ = newErr("created by a factory")
+	return nil
+}
+
+var lit = func() error {
+	return errorc.New("created per call") // want `errorc.New called inside a function`
+}
@@ -0,0 +1,7 @@
+package a
+
+import "github.com/ygrebnov/errorc"
+
+func testError() error {
+	return errorc.New("test error")
+}
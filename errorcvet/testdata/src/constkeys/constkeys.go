@@ -0,0 +1,25 @@
+package constkeys
+
+import (
+	"keys"
+
+	"github.com/ygrebnov/errorc"
+)
+
+type Key string
+
+const requestID Key = "request_id"
+
+var orderID = Key("order_id")
+
+func nonConstant(err error, key string) error {
+	local := Key("local")
+	return errorc.With(err,
+		errorc.String(key, "v"),       // want `errorc.String key is not a constant or a package-level variable`
+		errorc.Int(local, 1),          // want `errorc.Int key is not a constant or a package-level variable`
+		errorc.Lazy(Key("lazy"), nil), // constant conversion
+		errorc.String(requestID, "r1"),
+		errorc.String(orderID, "o1"),
+		errorc.String(keys.UserID, "u1"),
+	)
+}
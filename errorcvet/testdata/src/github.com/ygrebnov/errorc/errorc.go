@@ -0,0 +1,42 @@
+// Package errorc is a stub of github.com/ygrebnov/errorc declaring the API used by the tests.
+package errorc
+
+type Namespace string
+
+func (n Namespace) NewError(message string, opts ...Option) error { return nil }
+
+type Option func(*options)
+
+type options struct{}
+
+func WithNamespace(ns Namespace) Option { return nil }
+
+func WithCode(code string) Option { return nil }
+
+func Unrecorded() Option { return nil }
+
+func New(message string, opts ...Option) error { return nil }
+
+func ErrorFactory(ns Namespace) func(message string) error { return nil }
+
+func With(err error, fields ...field) error { return nil }
+
+type Renderer struct{}
+
+func (r *Renderer) With(err error, fields ...field) error { return nil }
+
+var Logfmt = Renderer{}
+
+type Field struct{}
+
+type field func() Field
+
+func String[K ~string](key K, value string) field { return nil }
+
+func Int[K ~string](key K, value int) field { return nil }
+
+func Error[K ~string](key K, err error) field { return nil }
+
+func Lazy[K ~string](key K, fn func() string) field { return nil }
+
+func Stack() field { return nil }
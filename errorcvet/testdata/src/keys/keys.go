@@ -0,0 +1,5 @@
+package keys
+
+type Key string
+
+var UserID = Key("user.id")
@@ -0,0 +1,13 @@
+package naming
+
+import "github.com/ygrebnov/errorc"
+
+func keys(err error) error {
+	return errorc.With(err,
+		errorc.String("user_id", "1"),
+		errorc.String("user.id", "1"),
+		errorc.String("", "value only"),
+		errorc.String("UserID", "1"), // want `errorc.String key "UserID" does not match the naming scheme \^\[a-z\]\[a-z0-9_.\]\*\$`
+		errorc.Int("retry-count", 1), // want `errorc.Int key "retry-count" does not match the naming scheme`
+	)
+}
@@ -1,8 +1,13 @@
 package errorc
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/ygrebnov/keys"
 )
@@ -103,3 +108,171 @@ func ExampleErrorFactory() {
 	fmt.Println(err)
 	// Output: storage: read_failed
 }
+
+// ExampleFields demonstrates reading back the fields attached by With.
+func ExampleFields() {
+	err := With(New("query failed"), String("user_id", "42"), Int("retries", 3))
+	for _, f := range Fields(err) {
+		fmt.Printf("%s=%s\n", f.Key(), f.Value())
+	}
+	// Output:
+	// user_id=42
+	// retries=3
+}
+
+// ExampleLookup demonstrates branching on a single field value.
+func ExampleLookup() {
+	err := With(New("rate limited"), String("retry_after", "5s"))
+	if f, ok := Lookup(err, "retry_after"); ok {
+		fmt.Println("retry after", f.Value())
+	}
+	// Output: retry after 5s
+}
+
+// ExampleAttrs demonstrates logging the fields of an error as slog attributes.
+func ExampleAttrs() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	err := With(New("query failed"), String("user_id", "42"))
+	logger.LogAttrs(context.Background(), slog.LevelError, err.Error(), Attrs(err)...)
+	// Output: level=ERROR msg="query failed, user_id: 42" user_id=42
+}
+
+// ExampleJSON demonstrates encoding an error chain as JSON.
+func ExampleJSON() {
+	err := With(With(New("not found"), String("id", "1")), Int("attempt", 2))
+
+	nested, _ := json.Marshal(err)
+	fmt.Println(string(nested))
+
+	flat, _ := JSON(err, WithJSONLayout(JSONFlat))
+	fmt.Println(string(flat))
+	// Output:
+	// {"message":"not found","fields":{"attempt":2},"cause":{"message":"not found","fields":{"id":"1"}}}
+	// {"message":"not found","fields":{"id":"1","attempt":2}}
+}
+
+func ExampleNamespace_Contains() {
+	storage := Namespace("storage")
+	err := With(storage.NewError("read_failed"), String("key", "k1"))
+
+	fmt.Println(storage.Contains(err), errors.Is(err, storage), errors.Is(err, Namespace("network")))
+	// Output: true true false
+}
+
+func ExampleRegistry() {
+	catalog := NewRegistry()
+	billing := catalog.Namespace("example_billing")
+
+	billing.NewError("card declined", WithCode("card_declined"), WithDescription("The card issuer declined the payment."))
+	billing.Child("refunds").NewError("refund window expired", WithCode("window_expired"))
+
+	for _, e := range catalog.Entries() {
+		fmt.Printf("%s %s: %s\n", e.Namespace, e.Code, e.Message)
+	}
+	// Output:
+	// example_billing card_declined: card declined
+	// example_billing/refunds window_expired: refund window expired
+}
+
+func ExampleRenderer_With() {
+	err := Logfmt.With(New("request failed"), String("path", "/a b"), Int("status", 503))
+	fmt.Println(err)
+
+	r := Renderer{FieldSeparator: "; ", KeyValueSeparator: "=", Quote: true}
+	fmt.Println(r.With(New("request failed"), String("note", "x; y"), Int("status", 503)))
+	// Output:
+	// request failed path="/a b" status=503
+	// request failed; note="x; y"; status=503
+}
+
+func ExampleParse() {
+	message, fields, err := Parse("request failed, path: /a, status: 503")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(message)
+	for _, f := range fields {
+		fmt.Printf("%s=%s\n", f.Key(), f.Value())
+	}
+
+	message, fields, _ = Logfmt.Parse(`request failed path="/a b"`)
+	fmt.Printf("%s: %q\n", message, fields[0].Value())
+	// Output:
+	// request failed
+	// path=/a
+	// status=503
+	// request failed: "/a b"
+}
+
+func ExampleWithContext() {
+	errNotFound := New("not found")
+
+	ctx := ContextWith(context.Background(), String("request_id", "r1"), String("tenant", "acme"))
+	err := WithContext(ctx, errNotFound, String("key", "k"), String("tenant", "globex"))
+	fmt.Println(err)
+	// Output: not found, request_id: r1, key: k, tenant: globex
+}
+
+func ExampleRegisterContextExtractor() {
+	type traceIDKey struct{}
+	RegisterContextExtractor(func(ctx context.Context) []Field {
+		if id, ok := ctx.Value(traceIDKey{}).(string); ok {
+			return []Field{String("trace_id", id).Field()}
+		}
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "4bf92f35")
+	fmt.Println(WithContext(ctx, New("not found"), String("key", "k")))
+	// Output: not found, trace_id: 4bf92f35, key: k
+}
+
+func ExampleIsRetryable() {
+	errConflict := New("conflict", WithClass(Retryable))
+
+	err := With(errConflict, RetryAfter(time.Second))
+	d, _ := RetryDelay(err)
+	fmt.Println(err, IsRetryable(err), d)
+
+	err = With(errConflict, Classify(Permanent))
+	fmt.Println(err, IsRetryable(err))
+
+	fmt.Println(IsRetryable(context.DeadlineExceeded))
+	// Output:
+	// conflict, retry_after: 1s true 1s
+	// conflict false
+	// true
+}
+
+func ExampleAppend() {
+	errRequired := New("required")
+	errTooShort := New("too short")
+
+	var err error
+	err = Append(err, With(errRequired, String("field", "name")))
+	err = Append(err, nil)
+	err = Append(err, With(errTooShort, String("field", "password"), Int("min", 8)))
+	err = With(err, String("request_id", "r1"))
+	fmt.Println(err)
+
+	var m *Multi
+	if errors.As(err, &m) {
+		for _, e := range m.Errors() {
+			f, _ := Lookup(e, "field")
+			fmt.Println(f.Value(), errors.Is(e, errTooShort))
+		}
+	}
+	// Output:
+	// 2 errors: [required, field: name; too short, field: password, min: 8], request_id: r1
+	// name false
+	// password true
+}
@@ -0,0 +1,219 @@
+package errorc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Kind is the kind of a Field value.
+type Kind uint8
+
+// Field value kinds.
+const (
+	KindString Kind = iota
+	KindInt64
+	KindUint64
+	KindBool
+	KindFloat64
+	KindDuration
+	KindTime
+	KindError
+	KindAny
+)
+
+// Kinds of the Fields returned by lazy fields and markers before they are attached. They are
+// never returned to callers: With moves markers to the error it returns, and lazy fields
+// are resolved when read.
+const (
+	kindLazy  Kind = iota + 0x80 // any holds the func() Field computing the value
+	kindStack                    // any holds the []uintptr recorded by Stack
+	kindClass                    // num holds the Class set by Classify
+)
+
+var kindNames = [...]string{
+	KindString:   "String",
+	KindInt64:    "Int64",
+	KindUint64:   "Uint64",
+	KindBool:     "Bool",
+	KindFloat64:  "Float64",
+	KindDuration: "Duration",
+	KindTime:     "Time",
+	KindError:    "Error",
+	KindAny:      "Any",
+}
+
+// String returns the name of the kind, for example "Int64".
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "<unknown errorc.Kind>"
+}
+
+// Field contains a key-value pair for additional context in an error.
+// Fields are attached to an error by With and can be read back using Fields and Lookup.
+//
+// A Field keeps the type of its value, reported by Kind. The value is converted to
+// its string representation only when rendered, for example by Value or by the
+// Error method of the error it is attached to.
+type Field struct {
+	key  string
+	kind Kind
+	sens Sensitivity
+	str  string // KindString value, KindError message, KindTime layout
+	num  uint64 // KindInt64, KindUint64, KindBool, KindFloat64, KindDuration values
+	any  any    // KindTime, KindError, KindAny values
+}
+
+// Key returns the field key. It is empty for fields created with an empty key.
+func (s Field) Key() string {
+	return s.key
+}
+
+// Kind returns the kind of the field value.
+func (s Field) Kind() Kind {
+	return s.kind
+}
+
+// Value returns the field value as it appears in the error message.
+func (s Field) Value() string {
+	if s.kind == KindString || s.kind == KindError {
+		return s.str
+	}
+	return string(s.appendValue(nil))
+}
+
+// Int64 returns the field value as an int64. It panics if the kind is not KindInt64.
+func (s Field) Int64() int64 {
+	s.mustBe(KindInt64)
+	return int64(s.num)
+}
+
+// Uint64 returns the field value as a uint64. It panics if the kind is not KindUint64.
+func (s Field) Uint64() uint64 {
+	s.mustBe(KindUint64)
+	return s.num
+}
+
+// Bool returns the field value as a bool. It panics if the kind is not KindBool.
+func (s Field) Bool() bool {
+	s.mustBe(KindBool)
+	return s.num == 1
+}
+
+// Float64 returns the field value as a float64. It panics if the kind is not KindFloat64.
+func (s Field) Float64() float64 {
+	s.mustBe(KindFloat64)
+	return math.Float64frombits(s.num)
+}
+
+// Duration returns the field value as a time.Duration. It panics if the kind is not KindDuration.
+func (s Field) Duration() time.Duration {
+	s.mustBe(KindDuration)
+	return time.Duration(s.num)
+}
+
+// Time returns the field value as a time.Time. It panics if the kind is not KindTime.
+func (s Field) Time() time.Time {
+	s.mustBe(KindTime)
+	return s.any.(time.Time)
+}
+
+// Err returns the error the field was created from. It panics if the kind is not KindError.
+func (s Field) Err() error {
+	s.mustBe(KindError)
+	return s.any.(error)
+}
+
+// Any returns the field value as an any. The dynamic type of the result matches
+// the kind: string, int64, uint64, bool, float64, time.Duration, time.Time, error,
+// or the value passed to the field constructor for KindAny.
+func (s Field) Any() any {
+	switch s.kind {
+	case KindString:
+		return s.str
+	case KindInt64:
+		return int64(s.num)
+	case KindUint64:
+		return s.num
+	case KindBool:
+		return s.num == 1
+	case KindFloat64:
+		return math.Float64frombits(s.num)
+	case KindDuration:
+		return time.Duration(s.num)
+	default:
+		return s.any
+	}
+}
+
+// field returns a field helper result returning s.
+func (s Field) field() field {
+	return func() Field {
+		return s
+	}
+}
+
+// resolve returns the Field computed by a lazy field, keeping the sensitivity set on s by
+// Sensitive or Secret, or s itself for other fields.
+func (s Field) resolve() Field {
+	if s.kind != kindLazy {
+		return s
+	}
+	v := s.any.(func() Field)()
+	if v.sens < s.sens {
+		v.sens = s.sens
+	}
+	return v
+}
+
+// marker reports whether s was created by a marker, such as Stack or Classify.
+func (s Field) marker() bool {
+	return s.kind == kindStack || s.kind == kindClass
+}
+
+func (s Field) mustBe(k Kind) {
+	if s.kind != k {
+		panic(fmt.Sprintf("errorc: Field kind is %s, not %s", s.kind, k))
+	}
+}
+
+// appendValue appends the string representation of the field value to b.
+func (s *Field) appendValue(b []byte) []byte {
+	switch s.kind {
+	case KindInt64:
+		return strconv.AppendInt(b, int64(s.num), 10)
+	case KindUint64:
+		return strconv.AppendUint(b, s.num, 10)
+	case KindBool:
+		return strconv.AppendBool(b, s.num == 1)
+	case KindFloat64:
+		return strconv.AppendFloat(b, math.Float64frombits(s.num), 'g', -1, 64)
+	case KindDuration:
+		return append(b, time.Duration(s.num).String()...)
+	case KindTime:
+		layout := s.str
+		if layout == "" {
+			layout = time.RFC3339Nano
+		}
+		return s.any.(time.Time).AppendFormat(b, layout)
+	case KindAny:
+		return fmt.Append(b, s.any)
+	default:
+		return append(b, s.str...)
+	}
+}
+
+// appendBytes appends the field to b in "key: value" format, or as "value" if the key is empty.
+func (s *Field) appendBytes(b []byte) []byte {
+	if s.key == "" {
+		return s.appendValue(b)
+	}
+
+	b = append(b, s.key...)
+	b = append(b, ':')
+	b = append(b, ' ')
+	return s.appendValue(b)
+}
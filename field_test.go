@@ -0,0 +1,78 @@
+package errorc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestField_kinds(t *testing.T) {
+	cause := errors.New("disk full")
+	err := With(New("base"), String("s", "v"), Int("i", -7), Bool("b", true), Error("e", cause))
+	fields := Fields(err)
+	if len(fields) != 4 {
+		t.Fatalf("got %d fields, want 4", len(fields))
+	}
+
+	tests := []struct {
+		kind  Kind
+		value string
+		any   any
+	}{
+		{KindString, "v", "v"},
+		{KindInt64, "-7", int64(-7)},
+		{KindBool, "true", true},
+		{KindError, "disk full", cause},
+	}
+	for i, tt := range tests {
+		f := fields[i]
+		if f.Kind() != tt.kind {
+			t.Errorf("fields[%d].Kind() = %s, want %s", i, f.Kind(), tt.kind)
+		}
+		if f.Value() != tt.value {
+			t.Errorf("fields[%d].Value() = %q, want %q", i, f.Value(), tt.value)
+		}
+		if f.Any() != tt.any {
+			t.Errorf("fields[%d].Any() = %v, want %v", i, f.Any(), tt.any)
+		}
+	}
+
+	if got := fields[1].Int64(); got != -7 {
+		t.Errorf("Int64() = %d, want -7", got)
+	}
+	if got := fields[2].Bool(); !got {
+		t.Errorf("Bool() = false, want true")
+	}
+	if got := fields[3].Err(); got != cause {
+		t.Errorf("Err() = %v, want %v", got, cause)
+	}
+}
+
+func TestField_kindMismatchPanics(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "errorc: Field kind is String, not Int64" {
+			t.Fatalf("recover() = %v, want kind mismatch panic", r)
+		}
+	}()
+	f, _ := Lookup(With(New("base"), String("s", "v")), "s")
+	_ = f.Int64()
+}
+
+func TestKind_String(t *testing.T) {
+	if got := KindDuration.String(); got != "Duration" {
+		t.Errorf("KindDuration.String() = %q, want 'Duration'", got)
+	}
+	if got := Kind(200).String(); got != "<unknown errorc.Kind>" {
+		t.Errorf("Kind(200).String() = %q", got)
+	}
+}
+
+func TestField_Field(t *testing.T) {
+	f := Int("attempt", 2).Field()
+	if f.Key() != "attempt" || f.Kind() != KindInt64 || f.Int64() != 2 {
+		t.Fatalf("Int(...).Field() = %q: %v (%s)", f.Key(), f.Value(), f.Kind())
+	}
+	if f := Lazy("k", nil).Field(); f.Key() != "" || f.Value() != "" {
+		t.Fatalf("nil field: Field() = %q: %q, want the zero Field", f.Key(), f.Value())
+	}
+}
@@ -0,0 +1,92 @@
+package errorc
+
+import "errors"
+
+// Fields returns the fields attached by With to err and to every error in its Unwrap chain.
+// Fields are returned in the order they appear in the error message: fields of the
+// innermost wrapped error first, then fields of each enclosing layer.
+// It returns nil if err is nil or carries no fields.
+func Fields(err error) []Field {
+	var layers []*errorWithFields
+	n := 0
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(*errorWithFields); ok {
+			layers = append(layers, e)
+			n += len(e.f)
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	fields := make([]Field, 0, n)
+	for i := len(layers) - 1; i >= 0; i-- {
+		fields = layers[i].fields(fields)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// WithFields works like With for fields given as Field values, for example fields built in
+// a loop with the Field method of field helpers, or returned by Fields:
+//
+//	fs := make([]Field, 0, len(params))
+//	for k, v := range params {
+//		fs = append(fs, String(k, v).Field())
+//	}
+//	return WithFields(ErrInvalidInput, fs)
+//
+// Zero Fields are ignored.
+func WithFields(err error, fields []Field) error {
+	if err == nil {
+		return nil
+	}
+	fs := make([]field, 0, len(fields))
+	for _, f := range fields {
+		if f != (Field{}) {
+			fs = append(fs, f.field())
+		}
+	}
+	e := &errorWithFields{e: err}
+	if !e.add(fs) {
+		return err
+	}
+	return e
+}
+
+// Lookup returns the field with the given key attached to err or to any error in its Unwrap chain.
+// If several fields share the key, the most recently attached one wins, that is the field
+// of the outermost layer, or the last one among the fields passed to a single With call.
+// The key can be any type whose underlying type is string, like in String.
+func Lookup[K ~string](err error, key K) (Field, bool) {
+	ks := string(key)
+	for ; err != nil; err = errors.Unwrap(err) {
+		e, ok := err.(*errorWithFields)
+		if !ok {
+			continue
+		}
+		for i := len(e.f) - 1; i >= 0; i-- {
+			if sf := e.f[i](); sf.key == ks {
+				return sf.resolve(), true
+			}
+		}
+	}
+	return Field{}, false
+}
+
+// Message returns the message of err without the fields attached by With, for exporters
+// that send fields separately: the message of the innermost error in its Unwrap chain.
+// If the chain ends with a Multi, the message is the number of its errors, for example
+// "2 errors", since the messages of the aggregated errors include their fields.
+// It returns an empty string if err is nil.
+func Message(err error) string {
+	if err == nil {
+		return ""
+	}
+	for next := errors.Unwrap(err); next != nil; next = errors.Unwrap(err) {
+		err = next
+	}
+	return jsonMessage(err)
+}
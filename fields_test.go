@@ -0,0 +1,116 @@
+package errorc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	if got := Fields(nil); got != nil {
+		t.Fatalf("Fields(nil) = %v, want nil", got)
+	}
+	if got := Fields(New("base")); got != nil {
+		t.Fatalf("Fields(plain error) = %v, want nil", got)
+	}
+
+	inner := With(New("base"), String("user_id", "42"), Int("count", 3))
+	middle := fmt.Errorf("handler: %w", inner)
+	outer := With(middle, Bool("cached", false), String("", "value-only"))
+
+	want := []struct{ key, value string }{
+		{"user_id", "42"},
+		{"count", "3"},
+		{"cached", "false"},
+		{"", "value-only"},
+	}
+	got := Fields(outer)
+	if len(got) != len(want) {
+		t.Fatalf("Fields() returned %d fields, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Key() != w.key || got[i].Value() != w.value {
+			t.Errorf("Fields()[%d] = %q: %q, want %q: %q", i, got[i].Key(), got[i].Value(), w.key, w.value)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	type keyType string
+	err := With(
+		fmt.Errorf("wrapped: %w", With(New("base"), String("user_id", "1"), String("retry_after", "5s"))),
+		String(keyType("user_id"), "2"),
+	)
+
+	f, ok := Lookup(err, "retry_after")
+	if !ok || f.Value() != "5s" {
+		t.Fatalf("Lookup(retry_after) = %q, %v, want '5s', true", f.Value(), ok)
+	}
+
+	f, ok = Lookup(err, keyType("user_id"))
+	if !ok || f.Value() != "2" {
+		t.Fatalf("Lookup(user_id) = %q, %v, want outermost value '2', true", f.Value(), ok)
+	}
+
+	same := With(New("base"), String("k", "first"), String("k", "second"))
+	if f, _ := Lookup(same, "k"); f.Value() != "second" {
+		t.Fatalf("Lookup(k) = %q, want 'second'", f.Value())
+	}
+
+	if _, ok := Lookup(err, "missing"); ok {
+		t.Fatalf("Lookup(missing) returned ok")
+	}
+	if _, ok := Lookup(nil, "k"); ok {
+		t.Fatalf("Lookup(nil) returned ok")
+	}
+	if _, ok := Lookup(errors.New("plain"), "k"); ok {
+		t.Fatalf("Lookup(plain error) returned ok")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	base := New("not found", WithNamespace("storage"))
+	multi := Append(nil, With(New("bad"), String("host", "db-7")), New("worse"))
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"plain", errors.New("boom"), "boom"},
+		{"fields", With(base, String("key", "k1")), "storage: not found"},
+		{"wrapped", fmt.Errorf("load: %w", With(base, String("key", "k1"))), "storage: not found"},
+		{"Multi", multi, "2 errors"},
+		{"Multi with shared fields", fmt.Errorf("validate: %w", With(multi, String("request_id", "r1"))), "2 errors"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Message(tt.err); got != tt.want {
+				t.Fatalf("Message() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	base := New("invalid input")
+	if err := WithFields(nil, []Field{String("k", "v").Field()}); err != nil {
+		t.Fatalf("WithFields(nil) = %v, want nil", err)
+	}
+	if err := WithFields(base, []Field{{}, Error("cause", nil).Field()}); err != base {
+		t.Fatalf("WithFields without non-zero fields = %v, want the original error", err)
+	}
+
+	err := WithFields(base, []Field{String("name", "required").Field(), {}, Sensitive(Int("age", 7)).Field()})
+	if got, want := err.Error(), "invalid input, name: required, age: ***"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+	if f, ok := Lookup(err, "age"); !ok || f.Int64() != 7 {
+		t.Fatalf("Lookup(age) = %v, %v", f, ok)
+	}
+
+	SetStackTraces(true)
+	defer SetStackTraces(false)
+	assertTopFrame(t, StackTrace(WithFields(base, []Field{String("k", "v").Field()})), "github.com/ygrebnov/errorc.TestWithFields")
+}
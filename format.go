@@ -0,0 +1,126 @@
+package errorc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Format implements fmt.Formatter:
+//   - %s, %v, %q, %x, and %X print the error message like a string, honoring the width,
+//     precision, and flags, for example %-20s or %.10v;
+//   - %+v prints the error message followed by a multi-line view of the Unwrap chain:
+//     the fields and the recorded stack of each layer, indented with a tab, and the
+//     message of each wrapped error on a "caused by: " line;
+//   - %#v prints a Go-syntax representation of the With call that would produce the error.
+func (e *errorWithFields) Format(s fmt.State, verb rune) {
+	format(s, verb, e)
+}
+
+// Format implements fmt.Formatter like (*errorWithFields).Format.
+// %#v prints a Go-syntax representation of the New call that would produce the error.
+func (e *sentinel) Format(s fmt.State, verb rune) {
+	format(s, verb, e)
+}
+
+func format(s fmt.State, verb rune, err error) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		writeVerbose(s, err)
+	case verb == 'v' && s.Flag('#'):
+		writeGoSyntax(s, err)
+	default:
+		_, _ = fmt.Fprintf(s, fmt.FormatString(s, verb), err.Error())
+	}
+}
+
+// writeVerbose writes the %+v representation of err.
+func writeVerbose(w io.Writer, err error) {
+	_, _ = io.WriteString(w, err.Error())
+	for {
+		if e, ok := err.(*errorWithFields); ok {
+			for _, f := range e.f {
+				sf := f.get()
+				if sf, ok := sf.redactFor(OutputText); ok {
+					_, _ = io.WriteString(w, "\n\t")
+					_, _ = w.Write(e.appendField(nil, &sf))
+				}
+			}
+		}
+		writeFrames(w, layerStack(err))
+
+		if err = errors.Unwrap(err); err == nil {
+			return
+		}
+		_, _ = io.WriteString(w, "\ncaused by: ")
+		_, _ = io.WriteString(w, err.Error())
+	}
+}
+
+// writeFrames writes the frames of a stack in the layout used by panics, indented
+// by one tab: the function name on one line and the file and line on the next.
+func writeFrames(w io.Writer, pcs []uintptr) {
+	for _, frame := range framesOf(pcs) {
+		_, _ = fmt.Fprintf(w, "\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line)
+	}
+}
+
+// writeGoSyntax writes the %#v representation of err.
+func writeGoSyntax(w io.Writer, err error) {
+	switch e := err.(type) {
+	case *sentinel:
+		if e.ns == "" && e.code == "" {
+			_, _ = fmt.Fprintf(w, "errorc.New(%q)", e.s)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "errorc.New(%q", e.msg)
+		// WithNamespace prepends, so the innermost namespace comes first.
+		for i := len(e.path) - 1; i >= 0; i-- {
+			_, _ = fmt.Fprintf(w, ", errorc.WithNamespace(%q)", e.path[i])
+		}
+		if e.code != "" {
+			_, _ = fmt.Fprintf(w, ", errorc.WithCode(%q)", e.code)
+		}
+		_, _ = io.WriteString(w, ")")
+	case *errorWithFields:
+		_, _ = fmt.Fprintf(w, "errorc.With(%#v", e.e)
+		if e.stack != nil {
+			_, _ = io.WriteString(w, ", errorc.Stack()")
+		}
+		if e.class != Unclassified {
+			_, _ = fmt.Fprintf(w, ", errorc.Classify(errorc.%s)", e.class)
+		}
+		for _, f := range e.f {
+			sf, ok := f.get().redactFor(OutputText)
+			if !ok {
+				continue
+			}
+			_, _ = io.WriteString(w, ", ")
+			sf.writeGoSyntax(w)
+		}
+		_, _ = io.WriteString(w, ")")
+	}
+}
+
+// writeGoSyntax writes a call to the field helper that creates a field like s.
+func (s *Field) writeGoSyntax(w io.Writer) {
+	switch s.sens {
+	case SensitivitySensitive:
+		_, _ = io.WriteString(w, "errorc.Sensitive(")
+	case SensitivitySecret:
+		_, _ = io.WriteString(w, "errorc.Secret(")
+	}
+
+	switch s.kind {
+	case KindTime:
+		_, _ = fmt.Fprintf(w, "errorc.TimeFormat(%q, %#v, %q)", s.key, s.any, s.str)
+	case KindError:
+		_, _ = fmt.Fprintf(w, "errorc.Error(%q, %#v)", s.key, s.any)
+	default:
+		_, _ = fmt.Fprintf(w, "errorc.%s(%q, %#v)", s.kind, s.key, s.Any())
+	}
+
+	if s.sens != SensitivityNone {
+		_, _ = io.WriteString(w, ")")
+	}
+}
@@ -0,0 +1,73 @@
+package errorc
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	inner := With(New("not found"), String("id", "1"), String("", "value-only"))
+	err := With(fmt.Errorf("load: %w", inner), Int("attempt", 2))
+
+	tests := []struct {
+		format string
+		err    error
+		want   string
+	}{
+		{"%v", err, "load: not found, id: 1, value-only, attempt: 2"},
+		{"%s", err, "load: not found, id: 1, value-only, attempt: 2"},
+		{"%q", With(New(`say "hi"`), String("k", "a\nb")), `"say \"hi\", k: a\nb"`},
+		{"%d", inner, "%!d(string=not found, id: 1, value-only)"},
+		{"%12v", New("base"), "        base"},
+		{"%-6s|", With(New("a"), String("", "b")), "a, b  |"},
+		{"%.4s", With(New("base"), String("k", "v")), "base"},
+		{"%x", With(New("a"), String("", "b")), "612c2062"},
+		{"%X", New("hi"), "6869"},
+		{"% x", New("hi"), "68 69"},
+		{"%8q", New("hi"), `    "hi"`},
+		{"%+v", err, "load: not found, id: 1, value-only, attempt: 2\n" +
+			"\tattempt: 2\n" +
+			"caused by: load: not found, id: 1, value-only\n" +
+			"caused by: not found, id: 1, value-only\n" +
+			"\tid: 1\n" +
+			"\tvalue-only\n" +
+			"caused by: not found"},
+		{"%v", New("base"), "base"},
+		{"%q", New("base"), `"base"`},
+		{"%+v", New("base"), "base"},
+		{"%#v", New("storage: read_failed"), `errorc.New("storage: read_failed")`},
+		{"%#v", With(New("base"), String("s", "v"), Int("i", 1), Bool("b", true), Duration("d", time.Second), Error("e", errors.New("eof"))),
+			`errorc.With(errorc.New("base"), errorc.String("s", "v"), errorc.Int64("i", 1), errorc.Bool("b", true), ` +
+				`errorc.Duration("d", 1000000000), errorc.Error("e", &errors.errorString{s:"eof"}))`},
+		{"%#v", With(inner, Float64("f", 0.5)),
+			`errorc.With(errorc.With(errorc.New("not found"), errorc.String("id", "1"), errorc.String("", "value-only")), errorc.Float64("f", 0.5))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.err); got != tt.want {
+				t.Fatalf("Sprintf(%s) = %q\nwant %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat_stack(t *testing.T) {
+	err := With(New("base", WithStack()), Stack(), String("k", "v"))
+
+	want := regexp.MustCompile(`^base, k: v\n` +
+		`\tk: v\n` +
+		`\tgithub\.com/ygrebnov/errorc\.TestFormat_stack\n\t\t.*/format_test\.go:\d+\n(\t.+\n\t\t.+:\d+\n)*` +
+		`caused by: base\n` +
+		`\tgithub\.com/ygrebnov/errorc\.TestFormat_stack\n\t\t.*/format_test\.go:\d+\n`)
+	if got := fmt.Sprintf("%+v", err); !want.MatchString(got) {
+		t.Fatalf("%%+v = %q, does not match %s", got, want)
+	}
+
+	if got := fmt.Sprintf("%#v", With(New("base"), Stack())); got != `errorc.With(errorc.New("base"), errorc.Stack())` {
+		t.Fatalf("%%#v = %q", got)
+	}
+}
@@ -2,12 +2,14 @@ package errorc
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"time"
 )
 
 // FuzzFormatting ensures that arbitrary unicode / empty strings for base error,
 // key, and value never cause a panic and always return a stable string.
-// It also exercises the Int / Bool / Error helpers with random inputs to
+// It also exercises the Int / Bool / Float64 / Duration / Error helpers with random inputs to
 // protect the unsafe path in (*errorWithFields).Error.
 func FuzzFormatting(f *testing.F) {
 	// Seed a few representative cases.
@@ -37,6 +39,8 @@ func FuzzFormatting(f *testing.F) {
 			String(key, val),
 			Int("n", int(n)),
 			Bool("flag", flag),
+			Float64("f", float64(n)/3),
+			Duration(key, time.Duration(n)),
 			Error("cause", maybeErr(val)),
 		}
 
@@ -67,3 +71,53 @@ func maybeErr(s string) error {
 	}
 	return errors.New(s)
 }
+
+// FuzzParse_roundTrip ensures that Renderer.Parse inverts the rendering of quoted formats:
+// the message and the keys and values of the fields are recovered exactly, within the
+// ambiguity rules documented on Renderer.Parse.
+func FuzzParse_roundTrip(f *testing.F) {
+	seeds := []struct{ msg, k1, v1, k2, v2 string }{
+		{"request failed", "path", "/a b", "status", "503"},
+		{"storage: not found", "key", "x, k: v", "", "value only"},
+		{"", "k", "", "", ""},
+		{"emoji 🚀", "ключ", "значение", "quote", `say "hi"`},
+		{"multi\nline", "k=v", "a\tb", "k", "\x00"},
+	}
+	for _, s := range seeds {
+		f.Add(s.msg, s.k1, s.v1, s.k2, s.v2)
+	}
+
+	renderers := []Renderer{
+		{Quote: true},
+		{Quote: true, Escape: true},
+		Logfmt,
+		{FieldSeparator: " | ", KeyValueSeparator: " -> ", Quote: true},
+	}
+
+	f.Fuzz(func(t *testing.T, msg, k1, v1, k2, v2 string) {
+		if len(msg)+len(k1)+len(v1)+len(k2)+len(v2) > 1<<12 {
+			return
+		}
+		if k1 == "" {
+			// Value-only fields preceding the first keyed field are parsed as part of the message.
+			return
+		}
+		for _, r := range renderers {
+			if m, fields, err := r.Parse(msg); err != nil || m != msg || fields != nil ||
+				strings.Contains(msg, r.fieldSeparator()+`"`) {
+				// The message itself looks like it carries fields.
+				continue
+			}
+
+			err := r.With(New(msg), String(k1, v1), String(k2, v2))
+			m, fields, perr := r.Parse(err.Error())
+			if perr != nil {
+				t.Fatalf("%+v: Parse(%q) returned %v", r, err.Error(), perr)
+			}
+			if m != msg || len(fields) != 2 ||
+				fields[0].Key() != k1 || fields[0].Value() != v1 || fields[1].Key() != k2 || fields[1].Value() != v2 {
+				t.Fatalf("%+v: Parse(%q) = %q, %v", r, err.Error(), m, fields)
+			}
+		}
+	})
+}
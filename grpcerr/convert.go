@@ -0,0 +1,186 @@
+package grpcerr
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// Option configures Status and the server interceptors.
+type Option func(*config)
+
+type config struct {
+	resolver *Resolver
+	export   errorc.ExportPolicy
+}
+
+// WithResolver sets the Resolver picking the code. By default, only the codes set with
+// WithStatusCode, the codes of wrapped gRPC status errors, and context errors are used.
+func WithResolver(r *Resolver) Option {
+	return func(c *config) {
+		c.resolver = r
+	}
+}
+
+// WithRedact sets a function reporting whether a field must be left out of the status
+// details, for example because it holds internal data. Applying WithRedact several times
+// leaves out the fields reported by any of the functions.
+func WithRedact(redact func(f errorc.Field) bool) Option {
+	return func(c *config) {
+		c.export.Omit(redact)
+	}
+}
+
+// WithRedactionPolicy sets the redaction policy applied to the fields marked with
+// errorc.Sensitive and errorc.Secret. The default is the policy of errorc.OutputExport.
+func WithRedactionPolicy(p errorc.RedactionPolicy) Option {
+	return func(c *config) {
+		c.export.SetRedactionPolicy(p)
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := config{resolver: &Resolver{}}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// Status converts err to a gRPC status. It returns a status with codes.OK if err is nil.
+//
+// If err implements GRPCStatus() *status.Status, like errors returned by gRPC calls and by
+// FromError, its status is returned unchanged. Otherwise:
+//   - the code is picked by the Resolver;
+//   - the message is the message of the error without the fields attached by errorc.With,
+//     as returned by errorc.Message: for an errorc.Multi, the number of its errors;
+//   - if err has an errorc code or fields, the details hold an errdetails.ErrorInfo whose
+//     Reason is the code, Domain is the namespace, and Metadata maps the keys of the fields
+//     not left out by WithRedact to their values, as returned by errorc.Field.Value.
+//     Sensitive and secret values are redacted according to the redaction policy.
+func Status(err error, opts ...Option) *status.Status {
+	return newConfig(opts).status(err)
+}
+
+func (c *config) status(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	if se, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return se.GRPCStatus()
+	}
+
+	st := status.New(c.resolver.Code(err), errorc.Message(err))
+
+	info := &errdetails.ErrorInfo{
+		Reason: errorc.Code(err),
+		Domain: string(errorc.NamespaceOf(err)),
+	}
+	for _, f := range c.export.Fields(err) {
+		if info.Metadata == nil {
+			info.Metadata = make(map[string]string)
+		}
+		// Later fields override earlier ones, like in errorc.Lookup.
+		info.Metadata[f.Key()] = f.Value()
+	}
+	if info.Reason == "" && info.Metadata == nil {
+		return st
+	}
+	if ds, derr := st.WithDetails(info); derr == nil {
+		return ds
+	}
+	return st
+}
+
+// FromStatus reconstructs the error described by st, as produced by Status.
+// It returns nil if st is nil or its code is codes.OK.
+//
+// If the details of st hold an errdetails.ErrorInfo, the error is created by errorc.New
+// with the namespace from its Domain and the code from its Reason, so that it matches the
+// declared error with errors.Is, and is wrapped by errorc.WithFields with a String field for
+// each metadata entry, in key order. The error is created with errorc.Unrecorded, so it
+// is not recorded by a Registry. Otherwise, the error is st.Err().
+//
+// The returned error implements GRPCStatus() *status.Status returning st, so that
+// status.FromError and Status return the original status.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if i, ok := d.(*errdetails.ErrorInfo); ok {
+			info = i
+			break
+		}
+	}
+	if info == nil {
+		return st.Err()
+	}
+
+	ns := errorc.Namespace(info.Domain)
+	var err error
+	if info.Reason != "" || ns != "" {
+		// Status sends the rendered message; strip the namespace prefix New adds again.
+		prefix := errorc.New("", errorc.WithNamespace(ns), errorc.Unrecorded()).Error()
+		msg := strings.TrimPrefix(st.Message(), prefix)
+		err = errorc.New(msg, errorc.WithNamespace(ns), errorc.WithCode(info.Reason), errorc.Unrecorded())
+	} else {
+		err = errors.New(st.Message())
+	}
+
+	keys := make([]string, 0, len(info.Metadata))
+	for k := range info.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]errorc.Field, len(keys))
+	for i, k := range keys {
+		fields[i] = errorc.String(k, info.Metadata[k]).Field()
+	}
+	err = errorc.WithFields(err, fields)
+	return &statusError{err: err, st: st}
+}
+
+// FromError reconstructs the error described by the gRPC status of err, like FromStatus.
+// It returns err unchanged if err is nil, has no gRPC status, or was returned by FromStatus.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*statusError); ok {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return FromStatus(st)
+}
+
+// statusError is the error returned by FromStatus.
+type statusError struct {
+	err error
+	st  *status.Status
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the reconstructed error.
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// GRPCStatus returns the status the error was reconstructed from.
+func (e *statusError) GRPCStatus() *status.Status {
+	return e.st
+}
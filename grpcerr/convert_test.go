@@ -0,0 +1,181 @@
+package grpcerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ygrebnov/errorc"
+	"github.com/ygrebnov/errorc/grpcerr"
+)
+
+var (
+	catalog     = errorc.NewRegistry()
+	storage     = catalog.Namespace("grpcerr_test/storage")
+	errNotFound = storage.NewError("not found", errorc.WithCode("not_found"), grpcerr.WithStatusCode(codes.NotFound))
+	errReadOnly = storage.NewError("read only", errorc.WithCode("read_only"), errorc.WithMetadata(grpcerr.MetadataKey, "FailedPrecondition"))
+	errInternal = storage.NewError("disk failure")
+)
+
+func TestCode(t *testing.T) {
+	var r grpcerr.Resolver
+	r.Map(errInternal, codes.DataLoss)
+
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"metadata", errorc.With(errNotFound, errorc.String("key", "k1")), codes.NotFound},
+		{"metadata name", fmt.Errorf("write: %w", errReadOnly), codes.FailedPrecondition},
+		{"mapping", errInternal, codes.DataLoss},
+		{"status error", fmt.Errorf("call: %w", status.Error(codes.Unavailable, "down")), codes.Unavailable},
+		{"context", fmt.Errorf("query: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{"unknown", errors.New("boom"), codes.Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Code(tt.err); got != tt.want {
+				t.Fatalf("Code() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := grpcerr.Code(errInternal); got != codes.Unknown {
+		t.Fatalf("Code() = %v, want %v", got, codes.Unknown)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	err := fmt.Errorf("load: %w", errorc.With(errNotFound,
+		errorc.String("key", "k1"),
+		errorc.Int("attempt", 2),
+		errorc.String("token", "secret"),
+	))
+	st := grpcerr.Status(err, grpcerr.WithRedact(func(f errorc.Field) bool { return f.Key() == "token" }))
+
+	if st.Code() != codes.NotFound || st.Message() != "grpcerr_test/storage: not found" {
+		t.Fatalf("Status() = %v, %q", st.Code(), st.Message())
+	}
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("Details() = %v, want one ErrorInfo", details)
+	}
+	info := details[0].(*errdetails.ErrorInfo)
+	if info.Reason != "not_found" || info.Domain != "grpcerr_test/storage" ||
+		!reflect.DeepEqual(info.Metadata, map[string]string{"key": "k1", "attempt": "2"}) {
+		t.Fatalf("ErrorInfo = %v", info)
+	}
+
+	if st := grpcerr.Status(errors.New("boom")); st.Code() != codes.Unknown || st.Message() != "boom" || len(st.Details()) != 0 {
+		t.Fatalf("Status() = %v", st)
+	}
+	if st := grpcerr.Status(nil); st.Code() != codes.OK {
+		t.Fatalf("Status(nil) = %v", st)
+	}
+	orig := status.New(codes.Aborted, "aborted")
+	if st := grpcerr.Status(orig.Err()); st.Code() != codes.Aborted || st.Message() != "aborted" {
+		t.Fatalf("Status() = %v, want the original status", st)
+	}
+}
+
+func TestStatus_redactionPolicy(t *testing.T) {
+	err := errorc.With(errNotFound, errorc.Sensitive(errorc.String("email", "a@example.com")), errorc.Secret(errorc.String("token", "t")))
+
+	info := grpcerr.Status(err).Details()[0].(*errdetails.ErrorInfo)
+	if !reflect.DeepEqual(info.Metadata, map[string]string{"email": "***"}) {
+		t.Fatalf("Metadata = %v, want the default policy applied", info.Metadata)
+	}
+	st := grpcerr.Status(err, grpcerr.WithRedactionPolicy(errorc.RedactionPolicy{Sensitive: errorc.RedactOmit, Secret: errorc.RedactNone}))
+	info = st.Details()[0].(*errdetails.ErrorInfo)
+	if !reflect.DeepEqual(info.Metadata, map[string]string{"token": "t"}) {
+		t.Fatalf("Metadata = %v", info.Metadata)
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	sent := errorc.With(errNotFound, errorc.String("key", "k1"), errorc.Int("attempt", 2))
+	st := grpcerr.Status(sent)
+
+	err := grpcerr.FromStatus(st)
+	if !errors.Is(err, errNotFound) || !errors.Is(err, storage) {
+		t.Fatalf("errors.Is(%v, errNotFound) = false, want true", err)
+	}
+	if errors.Is(err, errReadOnly) {
+		t.Fatalf("errors.Is(%v, errReadOnly) = true, want false", err)
+	}
+	if want := "grpcerr_test/storage: not found, attempt: 2, key: k1"; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+	if want := "grpcerr_test/storage: not found, attempt: 2, key: k1\n\tattempt: 2\n\tkey: k1\ncaused by: grpcerr_test/storage: not found"; fmt.Sprintf("%+v", errors.Unwrap(err)) != want {
+		t.Fatalf("%%+v = %q, want %q", fmt.Sprintf("%+v", errors.Unwrap(err)), want)
+	}
+	if f, ok := errorc.Lookup(err, "key"); !ok || f.Value() != "k1" {
+		t.Fatalf("Lookup() = %v, %v", f, ok)
+	}
+	if errorc.Code(err) != "not_found" {
+		t.Fatalf("Code() = %q", errorc.Code(err))
+	}
+	if got, ok := status.FromError(err); !ok || got != st {
+		t.Fatalf("status.FromError() = %v, %v, want the original status", got, ok)
+	}
+	if got := grpcerr.Status(err); got != st {
+		t.Fatalf("Status() = %v, want the original status", got)
+	}
+	if n := len(catalog.Entries()); n != 3 {
+		t.Fatalf("Entries() returned %d entries, want 3", n)
+	}
+
+	t.Run("fields only", func(t *testing.T) {
+		err := grpcerr.FromStatus(grpcerr.Status(errorc.With(errors.New("boom"), errorc.String("id", "1"))))
+		if err.Error() != "boom, id: 1" || grpcerr.Code(err) != codes.Unknown {
+			t.Fatalf("FromStatus() = %v", err)
+		}
+	})
+	t.Run("plain status", func(t *testing.T) {
+		st := status.New(codes.Unavailable, "down")
+		if err := grpcerr.FromStatus(st); err.Error() != st.Err().Error() || status.Code(err) != codes.Unavailable {
+			t.Fatalf("FromStatus() = %v", err)
+		}
+	})
+	t.Run("ok", func(t *testing.T) {
+		if err := grpcerr.FromStatus(status.New(codes.OK, "")); err != nil {
+			t.Fatalf("FromStatus() = %v, want nil", err)
+		}
+		if err := grpcerr.FromStatus(nil); err != nil {
+			t.Fatalf("FromStatus(nil) = %v, want nil", err)
+		}
+	})
+}
+
+func TestFromError(t *testing.T) {
+	if err := grpcerr.FromError(nil); err != nil {
+		t.Fatalf("FromError(nil) = %v", err)
+	}
+	plain := errors.New("plain")
+	if err := grpcerr.FromError(plain); err != plain {
+		t.Fatalf("FromError() = %v, want the original error", err)
+	}
+	err := grpcerr.FromError(grpcerr.Status(errNotFound).Err())
+	if !errors.Is(err, errNotFound) {
+		t.Fatalf("errors.Is(%v, errNotFound) = false, want true", err)
+	}
+	if again := grpcerr.FromError(err); again != err {
+		t.Fatalf("FromError() reconstructed a reconstructed error")
+	}
+}
+
+func TestStatus_multi(t *testing.T) {
+	err := errorc.Append(nil, errorc.With(errNotFound, errorc.String("internal_host", "db-7.corp")), errorc.New("other"))
+
+	st := grpcerr.Status(err, grpcerr.WithRedact(func(f errorc.Field) bool { return f.Key() == "internal_host" }))
+	if st.Code() != codes.NotFound || st.Message() != "2 errors" {
+		t.Fatalf("Status() = %v, %q, want NotFound, '2 errors'", st.Code(), st.Message())
+	}
+}
@@ -0,0 +1,28 @@
+package grpcerr_test
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ygrebnov/errorc"
+	"github.com/ygrebnov/errorc/grpcerr"
+)
+
+func ExampleFromError() {
+	var ErrQuotaExceeded = errorc.Namespace("billing").NewError("quota exceeded",
+		errorc.WithCode("quota_exceeded"), grpcerr.WithStatusCode(codes.ResourceExhausted))
+
+	// On the server.
+	sent := grpcerr.Status(errorc.With(ErrQuotaExceeded, errorc.Int("limit", 100))).Err()
+
+	// On the client.
+	err := grpcerr.FromError(sent)
+	fmt.Println(err)
+	fmt.Println(status.Code(err), errors.Is(err, ErrQuotaExceeded))
+	// Output:
+	// billing: quota exceeded, limit: 100
+	// ResourceExhausted true
+}
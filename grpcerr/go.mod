@@ -0,0 +1,22 @@
+module github.com/ygrebnov/errorc/grpcerr
+
+go 1.22.0
+
+require (
+	github.com/ygrebnov/errorc v0.6.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250115164207-1a7da9e5054f
+	google.golang.org/grpc v1.71.1
+)
+
+require (
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/protobuf v1.36.4 // indirect
+)
+
+// grpcerr uses errorc features released after v0.6.0. The replace directive only applies
+// when building this module in the repository; consumers get the required version above.
+// Release order: tag errorc first, then require that tag here, drop the replace directive,
+// and tag grpcerr/vX.Y.Z.
+replace github.com/ygrebnov/errorc => ../
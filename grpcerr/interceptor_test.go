@@ -0,0 +1,98 @@
+package grpcerr_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ygrebnov/errorc"
+	"github.com/ygrebnov/errorc/grpcerr"
+)
+
+// healthServer returns errorc errors from both a unary and a streaming method.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (healthServer) Check(_ context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return nil, errorc.With(errNotFound, errorc.String("service", req.GetService()), errorc.String("token", "secret"))
+}
+
+func (healthServer) Watch(req *healthpb.HealthCheckRequest, ss healthpb.Health_WatchServer) error {
+	if err := ss.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}); err != nil {
+		return err
+	}
+	return errorc.With(errReadOnly, errorc.String("service", req.GetService()))
+}
+
+func dial(t *testing.T, opts ...grpcerr.Option) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcerr.UnaryServerInterceptor(opts...)),
+		grpc.StreamInterceptor(grpcerr.StreamServerInterceptor(opts...)),
+	)
+	healthpb.RegisterHealthServer(srv, healthServer{})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpcerr.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(grpcerr.StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestInterceptors_unary(t *testing.T) {
+	client := dial(t, grpcerr.WithRedact(func(f errorc.Field) bool { return f.Key() == "token" }))
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "objects"})
+	if !errors.Is(err, errNotFound) {
+		t.Fatalf("errors.Is(%v, errNotFound) = false, want true", err)
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("status.Code() = %v, want %v", status.Code(err), codes.NotFound)
+	}
+	if f, ok := errorc.Lookup(err, "service"); !ok || f.Value() != "objects" {
+		t.Fatalf("Lookup(service) = %v, %v", f, ok)
+	}
+	if _, ok := errorc.Lookup(err, "token"); ok {
+		t.Fatalf("Lookup(token) found a redacted field")
+	}
+}
+
+func TestInterceptors_stream(t *testing.T) {
+	client := dial(t)
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "objects"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = stream.Recv()
+	if !errors.Is(err, errReadOnly) {
+		t.Fatalf("errors.Is(%v, errReadOnly) = false, want true", err)
+	}
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("status.Code() = %v, want %v", status.Code(err), codes.FailedPrecondition)
+	}
+	if want := "grpcerr_test/storage: read only, service: objects"; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+}
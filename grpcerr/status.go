@@ -0,0 +1,129 @@
+// Package grpcerr converts errors built with github.com/ygrebnov/errorc to gRPC statuses and back.
+//
+// On the server, Status converts an error to a *status.Status. Its code is picked by
+// a Resolver, for example from the WithStatusCode option of the sentinel error, and
+// its details hold an errdetails.ErrorInfo whose reason is the errorc code, whose
+// domain is the errorc namespace, and whose metadata holds the fields attached by
+// errorc.With:
+//
+//	var ErrNotFound = storage.NewError("not found", errorc.WithCode("not_found"), grpcerr.WithStatusCode(codes.NotFound))
+//
+//	return nil, grpcerr.Status(errorc.With(ErrNotFound, errorc.String("key", key))).Err()
+//
+// On the client, FromError reconstructs an error that matches ErrNotFound with errors.Is
+// and carries the fields, which can be read with errorc.Lookup.
+//
+// UnaryServerInterceptor, StreamServerInterceptor, UnaryClientInterceptor, and
+// StreamClientInterceptor apply the conversions to every call.
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// MetadataKey is the errorc metadata key holding the gRPC code of an error.
+// It is the key set by WithStatusCode and by errorc-gen for the grpc_code catalog attribute.
+const MetadataKey = "grpc.code"
+
+// WithStatusCode sets the gRPC code to respond with for an error created by errorc.New,
+// Namespace.NewError, or a generated constructor. It is stored as errorc metadata under MetadataKey.
+func WithStatusCode(code codes.Code) errorc.Option {
+	return errorc.WithMetadata(MetadataKey, code)
+}
+
+// Resolver picks the gRPC code of an error. The zero value is ready to use.
+//
+// The code is, in order of precedence:
+//   - the code set with WithStatusCode on the nearest error in the chain that has one,
+//     found in the same depth-first order as errors.As. The metadata value can also be
+//     the name of a code, like "NotFound", as generated by errorc-gen;
+//   - the code of the first mapping registered with Map whose target matches the
+//     error with errors.Is;
+//   - the code of the nearest error in the chain implementing GRPCStatus() *status.Status,
+//     like errors returned by gRPC calls;
+//   - codes.Canceled and codes.DeadlineExceeded for context.Canceled and context.DeadlineExceeded;
+//   - codes.Unknown.
+type Resolver struct {
+	mu    sync.RWMutex
+	rules []rule
+}
+
+type rule struct {
+	target error
+	code   codes.Code
+}
+
+// Map registers the code of errors matching target with errors.Is. The target can be
+// any error, including an errorc.Namespace to map every error declared under it.
+// Mappings are tried in the order they were registered.
+func (r *Resolver) Map(target error, code codes.Code) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, rule{target: target, code: code})
+}
+
+// Code returns the gRPC code of err. It returns codes.OK if err is nil.
+func (r *Resolver) Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	if v, ok := errorc.Metadata(err, MetadataKey); ok {
+		if code, ok := parseCode(v); ok {
+			return code
+		}
+	}
+
+	r.mu.RLock()
+	for _, rl := range r.rules {
+		if errors.Is(err, rl.target) {
+			r.mu.RUnlock()
+			return rl.code
+		}
+	}
+	r.mu.RUnlock()
+
+	var se interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &se) {
+		return se.GRPCStatus().Code()
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return codes.Unknown
+}
+
+// Code returns the gRPC code of err, like a Resolver without mappings.
+func Code(err error) codes.Code {
+	var r Resolver
+	return r.Code(err)
+}
+
+// codeNames maps the names of the codes, as returned by codes.Code.String, to the codes.
+var codeNames = func() map[string]codes.Code {
+	m := make(map[string]codes.Code, 17)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		m[c.String()] = c
+	}
+	return m
+}()
+
+func parseCode(v any) (codes.Code, bool) {
+	switch v := v.(type) {
+	case codes.Code:
+		return v, true
+	case string:
+		c, ok := codeNames[v]
+		return c, ok
+	}
+	return 0, false
+}
@@ -0,0 +1,27 @@
+package httperr_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/ygrebnov/errorc"
+	"github.com/ygrebnov/errorc/httperr"
+)
+
+func ExampleHandler() {
+	var ErrNotFound = errorc.Namespace("storage").NewError("not found",
+		errorc.WithCode("not_found"), httperr.WithStatus(http.StatusNotFound))
+
+	h := httperr.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errorc.With(ErrNotFound, errorc.String("key", "k1"), errorc.String("backend", "s3-eu-1"))
+	}, httperr.WithRedact(func(f errorc.Field) bool { return f.Key() == "backend" }))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/objects/k1", nil))
+	fmt.Println(rec.Code, rec.Header().Get("Content-Type"))
+	fmt.Print(rec.Body.String())
+	// Output:
+	// 404 application/problem+json
+	// {"title":"Not Found","status":404,"detail":"storage: not found","code":"not_found","fields":{"key":"k1"}}
+}
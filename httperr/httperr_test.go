@@ -0,0 +1,207 @@
+package httperr_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ygrebnov/errorc"
+	"github.com/ygrebnov/errorc/httperr"
+)
+
+var (
+	storage     = errorc.Namespace("storage")
+	errNotFound = storage.NewError("not found", errorc.WithCode("not_found"), httperr.WithStatus(http.StatusNotFound))
+	errConflict = storage.Child("s3").NewError("conflict", httperr.WithStatus(http.StatusConflict))
+	errInternal = storage.NewError("disk failure", errorc.WithCode("disk_failure"))
+)
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, http.StatusOK},
+		{"sentinel", errNotFound, http.StatusNotFound},
+		{"with fields", errorc.With(errNotFound, errorc.String("key", "k1")), http.StatusNotFound},
+		{"wrapped", fmt.Errorf("load: %w", errorc.With(errConflict, errorc.Int("version", 2))), http.StatusConflict},
+		{"nearest wins", errorc.With(errNotFound, errorc.Error("cause", errConflict)), http.StatusNotFound},
+		{"joined", errors.Join(errInternal, errConflict), http.StatusConflict},
+		{"without status", errInternal, http.StatusInternalServerError},
+		{"foreign", errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := httperr.Status(tt.err); got != tt.want {
+				t.Fatalf("Status() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_Map(t *testing.T) {
+	var r httperr.Resolver
+	r.Map(context.DeadlineExceeded, http.StatusGatewayTimeout)
+	r.Map(storage, http.StatusServiceUnavailable)
+	r.Map(errInternal, http.StatusTeapot) // shadowed by the namespace mapping
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"foreign", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"namespace", errInternal, http.StatusServiceUnavailable},
+		{"metadata first", errNotFound, http.StatusNotFound},
+		{"unmapped", errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Status(tt.err); got != tt.want {
+				t.Fatalf("Status() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := httperr.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		switch r.URL.Path {
+		case "/ok":
+			_, _ = w.Write([]byte("ok"))
+			return nil
+		case "/internal":
+			return errorc.With(errInternal, errorc.String("device", "sda"))
+		}
+		return errorc.With(errNotFound,
+			errorc.String("key", r.URL.Path),
+			errorc.Int("attempt", 2),
+			errorc.Bool("cached", false),
+			errorc.String("secret", "s3cr3t"),
+		)
+	},
+		httperr.WithRedact(func(f errorc.Field) bool { return f.Key() == "secret" }),
+		httperr.WithType(func(code string) string { return "https://example.com/errors/" + code }),
+	)
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/a", http.StatusNotFound, `{"type":"https://example.com/errors/not_found","title":"Not Found","status":404,"detail":"storage: not found","code":"not_found","fields":{"attempt":2,"cached":false,"key":"/a"}}`},
+		{"/internal", http.StatusInternalServerError, `{"type":"https://example.com/errors/disk_failure","title":"Internal Server Error","status":500,"code":"disk_failure"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != httperr.ContentType {
+				t.Fatalf("Content-Type = %q, want %q", ct, httperr.ContentType)
+			}
+			if got := rec.Body.String(); got != tt.body+"\n" {
+				t.Fatalf("body =\n%s\nwant\n%s", got, tt.body)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Fatalf("got %d %q, want 200 \"ok\"", rec.Code, rec.Body.String())
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	err := fmt.Errorf("save: %w", errorc.With(errConflict,
+		errorc.String("id", "1"),
+		errorc.Float64("ratio", 0.5),
+		errorc.Any("tags", []string{"a", "b"}),
+		errorc.Any("ch", make(chan int)),
+		errorc.String("id", "2"),
+	))
+	p := httperr.NewProblem(err,
+		httperr.WithRedact(func(f errorc.Field) bool { return f.Key() == "ch" }),
+		httperr.WithRedact(func(f errorc.Field) bool { return f.Key() == "ratio" }),
+	)
+
+	want := httperr.Problem{
+		Title:  "Conflict",
+		Status: http.StatusConflict,
+		Detail: "storage: s3: conflict",
+		Fields: map[string]any{"id": "2", "tags": []string{"a", "b"}},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Fatalf("NewProblem() = %#v, want %#v", p, want)
+	}
+	if _, err := json.Marshal(p); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewProblem_serverErrorFields(t *testing.T) {
+	err := errorc.With(errInternal, errorc.String("device", "sda"), errorc.String("host", "db1"))
+
+	if p := httperr.NewProblem(err); p.Fields != nil || p.Detail != "" {
+		t.Fatalf("NewProblem() exported %q and %v for a 5xx status", p.Detail, p.Fields)
+	}
+	p := httperr.NewProblem(err,
+		httperr.WithServerErrorFields(),
+		httperr.WithRedact(func(f errorc.Field) bool { return f.Key() == "host" }),
+	)
+	if want := map[string]any{"device": "sda"}; !reflect.DeepEqual(p.Fields, want) || p.Detail != "" {
+		t.Fatalf("NewProblem(WithServerErrorFields()) = %q, %v, want no detail and %v", p.Detail, p.Fields, want)
+	}
+}
+
+func TestWrite_unencodableField(t *testing.T) {
+	rec := httptest.NewRecorder()
+	httperr.Write(rec, errorc.With(errNotFound, errorc.Any("ch", make(chan int))))
+
+	var p struct {
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.Fields["ch"].(string); !ok {
+		t.Fatalf("fields = %v, want ch encoded as a string", p.Fields)
+	}
+}
+
+func TestNewProblem_redactionPolicy(t *testing.T) {
+	err := errorc.With(errNotFound, errorc.Sensitive(errorc.String("email", "a@example.com")), errorc.Secret(errorc.String("token", "t")))
+
+	if p := httperr.NewProblem(err); !reflect.DeepEqual(p.Fields, map[string]any{"email": "***"}) {
+		t.Fatalf("Fields = %v, want the default policy applied", p.Fields)
+	}
+	p := httperr.NewProblem(err, httperr.WithRedactionPolicy(errorc.RedactionPolicy{Sensitive: errorc.RedactNone, Secret: errorc.RedactOmit}))
+	if !reflect.DeepEqual(p.Fields, map[string]any{"email": "a@example.com"}) {
+		t.Fatalf("Fields = %v", p.Fields)
+	}
+}
+
+func TestNewProblem_multi(t *testing.T) {
+	errBad := errorc.New("bad", httperr.WithStatus(http.StatusBadRequest))
+	err := errorc.With(errorc.Append(nil, errorc.With(errBad, errorc.String("internal_host", "db-7.corp"))), errorc.String("request_id", "r1"))
+
+	p := httperr.NewProblem(err, httperr.WithRedact(func(f errorc.Field) bool { return f.Key() == "internal_host" }))
+	want := httperr.Problem{
+		Title:  "Bad Request",
+		Status: http.StatusBadRequest,
+		Detail: "1 error",
+		Fields: map[string]any{"request_id": "r1"},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Fatalf("NewProblem() = %#v, want %#v", p, want)
+	}
+}
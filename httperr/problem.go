@@ -0,0 +1,183 @@
+package httperr
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// ContentType is the media type of problem details defined by RFC 9457.
+const ContentType = "application/problem+json"
+
+// Problem is the RFC 9457 problem details representation of an error.
+type Problem struct {
+	// Type is a URI reference identifying the problem type. It is omitted, which means
+	// "about:blank", unless set with WithType.
+	Type string `json:"type,omitempty"`
+	// Title is the text of the HTTP status.
+	Title  string `json:"title,omitempty"`
+	Status int    `json:"status"`
+	// Detail is the message of the error without the fields attached by errorc.With, as
+	// returned by errorc.Message: for an errorc.Multi, the number of its errors.
+	// It is omitted for 5xx statuses.
+	Detail string `json:"detail,omitempty"`
+	// Code is the errorc code of the error, see errorc.Code.
+	Code string `json:"code,omitempty"`
+	// Fields holds the fields attached by errorc.With, except those left out by WithRedact.
+	// Like Detail, it is omitted for 5xx statuses, unless WithServerErrorFields is set.
+	// Sensitive and secret values are redacted according to the redaction policy.
+	// Numbers and booleans keep their type; other values are strings, except the values
+	// of errorc.Any fields, which are encoded using encoding/json.
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// Option configures NewProblem, Write, and Handler.
+type Option func(*config)
+
+type config struct {
+	resolver     *Resolver
+	export       errorc.ExportPolicy
+	typeURI      func(code string) string
+	serverFields bool
+}
+
+// WithResolver sets the Resolver picking the status. By default, only the statuses set
+// with WithStatus are used.
+func WithResolver(r *Resolver) Option {
+	return func(c *config) {
+		c.resolver = r
+	}
+}
+
+// WithRedact sets a function reporting whether a field must be left out of the problem
+// details, for example because it holds internal data. Applying WithRedact several times
+// leaves out the fields reported by any of the functions.
+func WithRedact(redact func(f errorc.Field) bool) Option {
+	return func(c *config) {
+		c.export.Omit(redact)
+	}
+}
+
+// WithType sets a function returning the problem type URI from the errorc code of
+// an error, for example to link to its documentation. It is not called for errors
+// without a code.
+func WithType(typeURI func(code string) string) Option {
+	return func(c *config) {
+		c.typeURI = typeURI
+	}
+}
+
+// WithRedactionPolicy sets the redaction policy applied to the fields marked with
+// errorc.Sensitive and errorc.Secret. The default is the policy of errorc.OutputExport.
+func WithRedactionPolicy(p errorc.RedactionPolicy) Option {
+	return func(c *config) {
+		c.export.SetRedactionPolicy(p)
+	}
+}
+
+// WithServerErrorFields includes the fields in the problem details of 5xx statuses. They
+// are left out by default, since server errors usually carry internal data, such as paths
+// or host names, that must not reach clients. WithRedact and the redaction policy still apply.
+func WithServerErrorFields() Option {
+	return func(c *config) {
+		c.serverFields = true
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := config{resolver: &Resolver{}}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// NewProblem returns the problem details describing err. err must not be nil.
+func NewProblem(err error, opts ...Option) Problem {
+	return newConfig(opts).problem(err)
+}
+
+func (c *config) problem(err error) Problem {
+	status := c.resolver.Status(err)
+	p := Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   errorc.Code(err),
+	}
+	if status < http.StatusInternalServerError {
+		p.Detail = errorc.Message(err)
+	}
+	if p.Code != "" && c.typeURI != nil {
+		p.Type = c.typeURI(p.Code)
+	}
+	if status >= http.StatusInternalServerError && !c.serverFields {
+		return p
+	}
+
+	for _, f := range c.export.Fields(err) {
+		if p.Fields == nil {
+			p.Fields = make(map[string]any)
+		}
+		// Later fields override earlier ones, like in errorc.Lookup.
+		p.Fields[f.Key()] = value(f)
+	}
+	return p
+}
+
+// value returns the JSON representation of the value of f.
+func value(f errorc.Field) any {
+	switch f.Kind() {
+	case errorc.KindInt64:
+		return f.Int64()
+	case errorc.KindUint64:
+		return f.Uint64()
+	case errorc.KindBool:
+		return f.Bool()
+	case errorc.KindFloat64:
+		// JSON has no representation for NaN and infinities; they are encoded as strings.
+		if v := f.Float64(); !math.IsNaN(v) && !math.IsInf(v, 0) {
+			return v
+		}
+	case errorc.KindAny:
+		if _, err := json.Marshal(f.Any()); err == nil {
+			return f.Any()
+		}
+	}
+	return f.Value()
+}
+
+// Write writes the problem details describing err to w with the status picked by
+// the Resolver and the application/problem+json content type. err must not be nil.
+func Write(w http.ResponseWriter, err error, opts ...Option) {
+	newConfig(opts).write(w, err)
+}
+
+func (c *config) write(w http.ResponseWriter, err error) {
+	p := c.problem(err)
+	b, jerr := json.Marshal(p)
+	if jerr != nil {
+		// Only the fields can fail to encode; respond without them.
+		p.Fields = nil
+		b, _ = json.Marshal(p)
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(append(b, '\n'))
+}
+
+// HandlerFunc is an HTTP handler returning an error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handler returns an http.Handler calling fn and, if fn returns an error, writing
+// its problem details like Write. fn must not write a response when it returns an error.
+func Handler(fn HandlerFunc, opts ...Option) http.Handler {
+	c := newConfig(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			c.write(w, err)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+// Package httperr translates errors built with github.com/ygrebnov/errorc to HTTP responses.
+//
+// Sentinel errors carry their HTTP status as metadata set with WithStatus:
+//
+//	var ErrNotFound = storage.NewError("not found", errorc.WithCode("not_found"), httperr.WithStatus(http.StatusNotFound))
+//
+// A Resolver picks the status of an error chain, and Handler adapts a handler returning
+// an error to an http.Handler writing RFC 9457 problem details:
+//
+//	http.Handle("/objects/", httperr.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//		return errorc.With(ErrNotFound, errorc.String("key", r.URL.Path))
+//	}))
+//	// HTTP/1.1 404 Not Found
+//	// Content-Type: application/problem+json
+//	//
+//	// {"title":"Not Found","status":404,"detail":"storage: not found","code":"not_found","fields":{"key":"/objects/a"}}
+package httperr
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// MetadataKey is the errorc metadata key holding the HTTP status of an error.
+// It is the key set by WithStatus and by errorc-gen for the http_status catalog attribute.
+const MetadataKey = "http.status"
+
+// WithStatus sets the HTTP status to respond with for an error created by errorc.New,
+// Namespace.NewError, or a generated constructor. It is stored as errorc metadata under MetadataKey.
+func WithStatus(status int) errorc.Option {
+	return errorc.WithMetadata(MetadataKey, status)
+}
+
+// Resolver picks the HTTP status of an error. The zero value is ready to use.
+//
+// The status is, in order of precedence:
+//   - the status set with WithStatus on the nearest error in the chain that has one,
+//     found in the same depth-first order as errors.As;
+//   - the status of the first mapping registered with Map whose target matches the
+//     error with errors.Is;
+//   - http.StatusInternalServerError.
+type Resolver struct {
+	mu    sync.RWMutex
+	rules []rule
+}
+
+type rule struct {
+	target error
+	status int
+}
+
+// Map registers the status of errors matching target with errors.Is. The target can be
+// any error, including an errorc.Namespace to map every error declared under it, or an
+// error from another package, like context.DeadlineExceeded. Mappings are tried in the
+// order they were registered.
+func (r *Resolver) Map(target error, status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, rule{target: target, status: status})
+}
+
+// Status returns the HTTP status of err. It returns http.StatusOK if err is nil.
+func (r *Resolver) Status(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if v, ok := errorc.Metadata(err, MetadataKey); ok {
+		if status, ok := v.(int); ok {
+			return status
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, rl := range r.rules {
+		if errors.Is(err, rl.target) {
+			return rl.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// Status returns the HTTP status of err set with WithStatus, like a Resolver without mappings.
+func Status(err error) int {
+	var r Resolver
+	return r.Status(err)
+}
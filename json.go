@@ -0,0 +1,279 @@
+package errorc
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+// JSONLayout selects how JSON represents an error chain.
+type JSONLayout uint8
+
+const (
+	// JSONNested represents each With layer as its own object. The error wrapped by
+	// a layer is serialized under the "cause" key.
+	JSONNested JSONLayout = iota
+	// JSONFlat represents the whole error chain as a single object holding the
+	// fields of every With layer.
+	JSONFlat
+)
+
+// JSONOption configures JSON.
+type JSONOption func(*jsonConfig)
+
+type jsonConfig struct {
+	layout JSONLayout
+	policy RedactionPolicy
+}
+
+// WithJSONLayout sets the JSON layout. The default layout is JSONNested.
+func WithJSONLayout(layout JSONLayout) JSONOption {
+	return func(c *jsonConfig) {
+		c.layout = layout
+	}
+}
+
+// WithJSONRedactionPolicy sets the redaction policy applied to sensitive fields.
+// The default is the policy of OutputJSON, see SetRedactionPolicy.
+func WithJSONRedactionPolicy(p RedactionPolicy) JSONOption {
+	return func(c *jsonConfig) {
+		c.policy = p
+	}
+}
+
+// JSON returns the JSON encoding of err. It returns the bytes "null" if err is nil.
+//
+// An error produced by With is encoded as an object with the following keys:
+//   - "message": the message of the error wrapped by the With calls, without fields;
+//   - "namespace" and "code": the namespace and code of that error, if it was created
+//     by New with WithCode;
+//   - "fields": an object holding the fields. Numbers and booleans are encoded as JSON
+//     numbers and booleans, Any values using encoding/json, Error fields wrapping errors
+//     produced by With as nested objects, and other values as strings;
+//   - "cause": in the JSONNested layout, the encoding of the next error in the Unwrap chain,
+//     omitted when there is none.
+//
+// In the JSONFlat layout, "fields" holds the fields of the whole Unwrap chain and "cause" is omitted.
+// If several fields in one object share a key, the most recently attached one wins, like in Lookup.
+// Sensitive and secret fields are redacted according to the redaction policy.
+// Any other error is encoded as an object with a "message" key, the "namespace" and "code"
+// keys as above, and, in the JSONNested layout, a "cause" key.
+//
+// A Multi, wrapped by With or not, is encoded with the number of its errors as the message
+// and the encoding of each error, in the same layout, under the "errors" key.
+func JSON(err error, opts ...JSONOption) ([]byte, error) {
+	c := jsonConfig{policy: RedactionPolicyOf(OutputJSON)}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if err == nil {
+		return []byte("null"), nil
+	}
+	return c.appendError(nil, err)
+}
+
+// MarshalJSON implements json.Marshaler using the JSONNested layout.
+func (e *errorWithFields) MarshalJSON() ([]byte, error) {
+	return JSON(e)
+}
+
+func (c *jsonConfig) appendError(b []byte, err error) ([]byte, error) {
+	e, ok := err.(*errorWithFields)
+	if !ok {
+		b, err2 := appendJSONKeyString(append(b, '{'), "message", jsonMessage(err))
+		if err2 != nil {
+			return nil, err2
+		}
+		if b, err2 = appendIdentity(b, err); err2 != nil {
+			return nil, err2
+		}
+		if c.layout == JSONFlat {
+			if b, err2 = c.appendFields(b, Fields(err)); err2 != nil {
+				return nil, err2
+			}
+		}
+		if b, err2 = c.appendMulti(b, err); err2 != nil {
+			return nil, err2
+		}
+		return c.appendCause(b, errors.Unwrap(err))
+	}
+
+	// Find the first error below the directly nested With layers: its message is the
+	// message of the whole group.
+	inner := e.e
+	for {
+		ie, ok := inner.(*errorWithFields)
+		if !ok {
+			break
+		}
+		inner = ie.e
+	}
+
+	b, err = appendJSONKeyString(append(b, '{'), "message", jsonMessage(inner))
+	if err != nil {
+		return nil, err
+	}
+	if b, err = appendIdentity(b, inner); err != nil {
+		return nil, err
+	}
+
+	var fields []Field
+	if c.layout == JSONFlat {
+		fields = Fields(e)
+	} else {
+		fields = e.fields(make([]Field, 0, len(e.f)))
+	}
+	if b, err = c.appendFields(b, fields); err != nil {
+		return nil, err
+	}
+	if b, err = c.appendMulti(b, inner); err != nil {
+		return nil, err
+	}
+
+	if c.layout == JSONFlat {
+		return append(b, '}'), nil
+	}
+	// A layer wrapping another With layer nests it; otherwise the wrapped error's
+	// message is already represented and encoding continues below it.
+	if _, ok := e.e.(*errorWithFields); ok {
+		return c.appendCause(b, e.e)
+	}
+	return c.appendCause(b, errors.Unwrap(e.e))
+}
+
+// jsonMessage returns the message of err. The message of a Multi is the number of its
+// errors, which are encoded under the "errors" key.
+func jsonMessage(err error) string {
+	if m, ok := err.(*Multi); ok {
+		return string(m.appendSummary(nil))
+	}
+	return err.Error()
+}
+
+// appendMulti appends the "errors" key holding the encoding of every error of err if it is a Multi.
+func (c *jsonConfig) appendMulti(b []byte, err error) ([]byte, error) {
+	m, ok := err.(*Multi)
+	if !ok {
+		return b, nil
+	}
+	b = append(b, `,"errors":[`...)
+	for i, child := range m.errs {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var jerr error
+		if b, jerr = c.appendError(b, child); jerr != nil {
+			return nil, jerr
+		}
+	}
+	return append(b, ']'), nil
+}
+
+// appendIdentity appends the "namespace" and "code" keys if err was created by New with a code.
+func appendIdentity(b []byte, err error) ([]byte, error) {
+	s, ok := err.(*sentinel)
+	if !ok || s.code == "" {
+		return b, nil
+	}
+
+	var jerr error
+	if s.ns != "" {
+		b = append(b, ',')
+		if b, jerr = appendJSONKeyString(b, "namespace", string(s.ns)); jerr != nil {
+			return nil, jerr
+		}
+	}
+	return appendJSONKeyString(append(b, ','), "code", s.code)
+}
+
+// appendCause appends the "cause" key and closes the object opened by appendError.
+func (c *jsonConfig) appendCause(b []byte, cause error) ([]byte, error) {
+	if cause == nil || c.layout == JSONFlat {
+		return append(b, '}'), nil
+	}
+	b = append(b, `,"cause":`...)
+	b, err := c.appendError(b, cause)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '}'), nil
+}
+
+func (c *jsonConfig) appendFields(b []byte, fields []Field) ([]byte, error) {
+	if len(fields) == 0 {
+		return b, nil
+	}
+
+	b = append(b, `,"fields":{`...)
+	first := true
+	for i := range fields {
+		if shadowed(fields, i) {
+			continue
+		}
+		f, ok := fields[i].Redact(c.policy)
+		if !ok {
+			continue
+		}
+		if !first {
+			b = append(b, ',')
+		}
+		first = false
+
+		var err error
+		if b, err = appendJSONString(b, f.key); err != nil {
+			return nil, err
+		}
+		b = append(b, ':')
+		if b, err = c.appendValue(b, &f); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+func (c *jsonConfig) appendValue(b []byte, f *Field) ([]byte, error) {
+	switch f.kind {
+	case KindInt64, KindUint64, KindBool:
+		return f.appendValue(b), nil
+	case KindFloat64:
+		// JSON has no representation for NaN and infinities; they are encoded as strings.
+		if v := math.Float64frombits(f.num); !math.IsNaN(v) && !math.IsInf(v, 0) {
+			return f.appendValue(b), nil
+		}
+	case KindError:
+		if _, ok := f.any.(*errorWithFields); ok {
+			return c.appendError(b, f.any.(error))
+		}
+	case KindAny:
+		if v, err := json.Marshal(f.any); err == nil {
+			return append(b, v...), nil
+		}
+	}
+	return appendJSONString(b, f.Value())
+}
+
+// shadowed reports whether fields[i] is overridden by a later field with the same key.
+func shadowed(fields []Field, i int) bool {
+	for j := i + 1; j < len(fields); j++ {
+		if fields[j].key == fields[i].key {
+			return true
+		}
+	}
+	return false
+}
+
+func appendJSONKeyString(b []byte, key, value string) ([]byte, error) {
+	b, err := appendJSONString(b, key)
+	if err != nil {
+		return nil, err
+	}
+	return appendJSONString(append(b, ':'), value)
+}
+
+func appendJSONString(b []byte, s string) ([]byte, error) {
+	q, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, q...), nil
+}
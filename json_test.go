@@ -0,0 +1,123 @@
+package errorc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestJSON(t *testing.T) {
+	notFound := New("not found")
+	inner := With(notFound, String("id", "1"), Int("attempt", 1))
+	cause := With(New("disk full"), Int("free", 0))
+
+	tests := []struct {
+		name string
+		err  error
+		opts []JSONOption
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: `null`,
+		},
+		{
+			name: "plain error",
+			err:  errors.New("boom"),
+			want: `{"message":"boom"}`,
+		},
+		{
+			name: "typed fields",
+			err:  With(notFound, String("id", "1"), Int("n", -2), Bool("ok", true), String("", "v")),
+			want: `{"message":"not found","fields":{"id":"1","n":-2,"ok":true,"":"v"}}`,
+		},
+		{
+			name: "nested layers",
+			err:  With(inner, Int("attempt", 2)),
+			want: `{"message":"not found","fields":{"attempt":2},"cause":{"message":"not found","fields":{"id":"1","attempt":1}}}`,
+		},
+		{
+			name: "non-With layer in chain",
+			err:  With(fmt.Errorf("handler: %w", inner), Bool("cached", false)),
+			want: `{"message":"handler: not found, id: 1, attempt: 1","fields":{"cached":false},"cause":{"message":"not found","fields":{"id":"1","attempt":1}}}`,
+		},
+		{
+			name: "flat layout",
+			err:  With(inner, Int("attempt", 2)),
+			opts: []JSONOption{WithJSONLayout(JSONFlat)},
+			want: `{"message":"not found","fields":{"id":"1","attempt":2}}`,
+		},
+		{
+			name: "flat layout with non-With top",
+			err:  fmt.Errorf("handler: %w", inner),
+			opts: []JSONOption{WithJSONLayout(JSONFlat)},
+			want: `{"message":"handler: not found, id: 1, attempt: 1","fields":{"id":"1","attempt":1}}`,
+		},
+		{
+			name: "error fields",
+			err:  With(New("save failed"), Error("cause", cause), Error("plain", errors.New("eof"))),
+			want: `{"message":"save failed","fields":{"cause":{"message":"disk full","fields":{"free":0}},"plain":"eof"}}`,
+		},
+		{
+			name: "duplicate keys",
+			err:  With(New("x"), String("k", "1"), String("j", "2"), String("k", "3")),
+			want: `{"message":"x","fields":{"j":"2","k":"3"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSON(tt.err, tt.opts...)
+			if err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("JSON() = %s\nwant %s", got, tt.want)
+			}
+			if !json.Valid(got) {
+				t.Fatalf("JSON() produced invalid JSON: %s", got)
+			}
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	payload := struct {
+		Error error `json:"error"`
+	}{
+		Error: With(New(`quote " and <tag>`), String("user_id", "42")),
+	}
+
+	got, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"error":{"message":"quote \" and \u003ctag\u003e","fields":{"user_id":"42"}}}`
+	if string(got) != want {
+		t.Fatalf("json.Marshal() = %s\nwant %s", got, want)
+	}
+}
+
+func TestJSON_typedValues(t *testing.T) {
+	err := With(New("x"),
+		Uint64("u", 7),
+		Float64("f", 0.5),
+		Float64("nan", math.NaN()),
+		Duration("d", time.Second),
+		Time("t", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)),
+		Any("slice", []int{1, 2}),
+		Any("ch", make(chan int)),
+	)
+	got, jerr := JSON(err)
+	if jerr != nil {
+		t.Fatalf("JSON() error = %v", jerr)
+	}
+	want := `{"message":"x","fields":{"u":7,"f":0.5,"nan":"NaN","d":"1s","t":"2025-01-02T00:00:00Z","slice":[1,2],"ch":"` + fmt.Sprint(Fields(err)[6].Any()) + `"}}`
+	if string(got) != want {
+		t.Fatalf("JSON() = %s\nwant %s", got, want)
+	}
+}
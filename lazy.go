@@ -0,0 +1,86 @@
+package errorc
+
+import (
+	"math"
+	"sync"
+)
+
+// Lazy creates a field whose value is computed by fn only when the field is first read:
+// when the error is rendered by Error or encoded, or when the field is returned by Fields
+// or Lookup. fn is called at most once, and its result is reused afterwards.
+// Errors that are only compared with errors.Is or errors.As never call fn.
+// If fn is nil it returns nil so that it will be ignored by With().
+//
+// Methods can be passed as fn directly, for example Lazy("request", req.String).
+func Lazy[K ~string](key K, fn func() string) field {
+	if fn == nil {
+		return nil
+	}
+	ks := string(key)
+	return lazy(ks, func() Field {
+		return Field{key: ks, kind: KindString, str: fn()}
+	})
+}
+
+// LazyInt64 is like Lazy for a value of kind KindInt64.
+func LazyInt64[K ~string](key K, fn func() int64) field {
+	if fn == nil {
+		return nil
+	}
+	ks := string(key)
+	return lazy(ks, func() Field {
+		return Field{key: ks, kind: KindInt64, num: uint64(fn())}
+	})
+}
+
+// LazyFloat64 is like Lazy for a value of kind KindFloat64.
+func LazyFloat64[K ~string](key K, fn func() float64) field {
+	if fn == nil {
+		return nil
+	}
+	ks := string(key)
+	return lazy(ks, func() Field {
+		return Field{key: ks, kind: KindFloat64, num: math.Float64bits(fn())}
+	})
+}
+
+// LazyBool is like Lazy for a value of kind KindBool.
+func LazyBool[K ~string](key K, fn func() bool) field {
+	if fn == nil {
+		return nil
+	}
+	ks := string(key)
+	return lazy(ks, func() Field {
+		var n uint64
+		if fn() {
+			n = 1
+		}
+		return Field{key: ks, kind: KindBool, num: n}
+	})
+}
+
+// LazyAny is like Lazy for a value of any type. The field kind is chosen from the
+// dynamic type of the computed value, like in Any. A computed nil error or nil
+// fmt.Stringer is kept with KindAny and rendered as "<nil>".
+func LazyAny[K ~string](key K, fn func() any) field {
+	if fn == nil {
+		return nil
+	}
+	ks := string(key)
+	return lazy(ks, func() Field {
+		if f := Any(ks, fn()); f != nil {
+			return f()
+		}
+		return Field{key: ks, kind: KindAny}
+	})
+}
+
+// lazy returns a field with the given key whose value is computed by value when the field
+// is first read. Calling the field returns a Field of kind kindLazy, so that its key can be
+// read without computing the value.
+func lazy(key string, value func() Field) field {
+	value = sync.OnceValue(value)
+	return func() Field {
+		return Field{key: key, kind: kindLazy, any: value}
+	}
+}
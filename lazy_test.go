@@ -0,0 +1,60 @@
+package errorc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	calls := 0
+	sentinel := New("not found")
+	err := With(sentinel, Lazy("dump", func() string {
+		calls++
+		return "expensive"
+	}))
+
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("errors.Is() = false, want true")
+	}
+	if calls != 0 {
+		t.Fatalf("fn called %d times before rendering, want 0", calls)
+	}
+
+	for i := 0; i < 3; i++ {
+		if got := err.Error(); got != "not found, dump: expensive" {
+			t.Fatalf("Error() = %q, want 'not found, dump: expensive'", got)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("fn called %d times, want 1", calls)
+	}
+
+	if Lazy("k", nil) != nil {
+		t.Fatalf("Lazy(nil) should return nil")
+	}
+}
+
+func TestLazy_typed(t *testing.T) {
+	var nilErr error
+	err := With(New("base"),
+		LazyInt64("n", func() int64 { return -3 }),
+		LazyFloat64("f", func() float64 { return 0.5 }),
+		LazyBool("b", func() bool { return true }),
+		LazyAny("a", func() any { return uint8(7) }),
+		LazyAny("e", func() any { return nilErr }),
+	)
+	if got, want := err.Error(), "base, n: -3, f: 0.5, b: true, a: 7, e: <nil>"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+
+	wantKinds := []Kind{KindInt64, KindFloat64, KindBool, KindUint64, KindAny}
+	for i, f := range Fields(err) {
+		if f.Kind() != wantKinds[i] {
+			t.Errorf("Fields()[%d].Kind() = %s, want %s", i, f.Kind(), wantKinds[i])
+		}
+	}
+
+	if LazyInt64("k", nil) != nil || LazyFloat64("k", nil) != nil || LazyBool("k", nil) != nil || LazyAny("k", nil) != nil {
+		t.Fatalf("typed Lazy helpers should return nil for a nil fn")
+	}
+}
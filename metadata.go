@@ -0,0 +1,43 @@
+package errorc
+
+// WithDescription sets a longer, human-readable description of an error created by New.
+// It does not change the error message; it is recorded by the Registry the error's
+// namespace belongs to, for example to generate documentation.
+func WithDescription(description string) Option {
+	return func(o *options) {
+		o.description = description
+	}
+}
+
+// WithMetadata attaches a metadata value under key to an error created by New.
+// Unlike fields attached by With, metadata is not rendered in the error message; it
+// describes the error declaration itself, for example a status code to respond with.
+// Metadata can be read with Metadata and is recorded by the Registry the error's
+// namespace belongs to. Applying WithMetadata with the same key again replaces the value.
+func WithMetadata(key string, value any) Option {
+	return func(o *options) {
+		if o.metadata == nil {
+			o.metadata = make(map[string]any, 1)
+		}
+		o.metadata[key] = value
+	}
+}
+
+// Metadata returns the metadata value attached under key with WithMetadata to the nearest
+// error in the tree of errors wrapped by err that has this key. The tree is traversed in
+// the same depth-first order as errors.As.
+func Metadata(err error, key string) (any, bool) {
+	var value any
+	found := walk(err, func(err error) bool {
+		s, ok := err.(*sentinel)
+		if !ok {
+			return false
+		}
+		v, ok := s.metadata[key]
+		if ok {
+			value = v
+		}
+		return ok
+	})
+	return value, found
+}
@@ -0,0 +1,124 @@
+package errorc
+
+import "strconv"
+
+// Multi is an error aggregating several errors, for example the failures collected while
+// validating a request. It is created by Append. Unlike errors.Join, it renders every
+// error on a single line with its fields:
+//
+//	2 errors: [required, field: name; too short, field: password, min: 8]
+//
+// Multi implements Unwrap() []error, so errors.Is and errors.As match any of its errors.
+// Fields shared by all the errors, such as a request ID, are attached by wrapping the
+// Multi with With; they are rendered after the brackets.
+type Multi struct {
+	errs []error
+}
+
+// Append returns an error aggregating err and errs. Nil errors are ignored.
+//
+// If err is a Multi, or a Multi wrapped by With, errs are appended to a copy of it, keeping
+// the fields attached by With. Otherwise, a new Multi holds err, unless it is nil, followed
+// by errs. If errs holds no non-nil error, Append returns err, so that errors can be collected
+// in a loop starting with a nil error:
+//
+//	var err error
+//	for _, f := range form {
+//		err = Append(err, validate(f))
+//	}
+func Append(err error, errs ...error) error {
+	n := 0
+	for _, e := range errs {
+		if e != nil {
+			n++
+		}
+	}
+	if n == 0 {
+		return err
+	}
+	if merged := appendToMulti(err, errs, n); merged != nil {
+		return merged
+	}
+
+	m := &Multi{errs: make([]error, 0, n+1)}
+	if err != nil {
+		m.errs = append(m.errs, err)
+	}
+	m.errs = appendNonNil(m.errs, errs)
+	return m
+}
+
+// appendToMulti appends n non-nil errors of errs to a copy of the Multi err is or directly
+// wraps with With, and returns the copy wrapped by copies of the With layers. It returns nil
+// if err is not such an error.
+func appendToMulti(err error, errs []error, n int) error {
+	switch e := err.(type) {
+	case *Multi:
+		m := &Multi{errs: make([]error, 0, len(e.errs)+n)}
+		m.errs = append(m.errs, e.errs...)
+		m.errs = appendNonNil(m.errs, errs)
+		return m
+	case *errorWithFields:
+		inner := appendToMulti(e.e, errs, n)
+		if inner == nil {
+			return nil
+		}
+		c := *e
+		c.e = inner
+		return &c
+	}
+	return nil
+}
+
+func appendNonNil(dst, errs []error) []error {
+	for _, e := range errs {
+		if e != nil {
+			dst = append(dst, e)
+		}
+	}
+	return dst
+}
+
+// Error returns the number of errors followed by their messages, separated by "; " and
+// enclosed in brackets.
+func (m *Multi) Error() string {
+	b := m.appendSummary(nil)
+	b = append(b, ": ["...)
+	for i, err := range m.errs {
+		if i > 0 {
+			b = append(b, "; "...)
+		}
+		b = append(b, err.Error()...)
+	}
+	b = append(b, ']')
+	return string(b)
+}
+
+// appendSummary appends the number of errors to b, for example "2 errors".
+func (m *Multi) appendSummary(b []byte) []byte {
+	b = strconv.AppendInt(b, int64(len(m.errs)), 10)
+	if len(m.errs) == 1 {
+		return append(b, " error"...)
+	}
+	return append(b, " errors"...)
+}
+
+// Unwrap returns the aggregated errors.
+func (m *Multi) Unwrap() []error {
+	return m.errs
+}
+
+// Errors returns a copy of the aggregated errors, in the order they were appended.
+func (m *Multi) Errors() []error {
+	return append([]error(nil), m.errs...)
+}
+
+// Len returns the number of aggregated errors.
+func (m *Multi) Len() int {
+	return len(m.errs)
+}
+
+// MarshalJSON implements json.Marshaler using the JSONNested layout.
+func (m *Multi) MarshalJSON() ([]byte, error) {
+	return JSON(m)
+}
@@ -0,0 +1,120 @@
+package errorc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	errRequired := New("required")
+	errTooShort := New("too short")
+	a := With(errRequired, String("field", "name"))
+	b := With(errTooShort, String("field", "password"), Int("min", 8))
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nothing", Append(nil), ""},
+		{"nil errors", Append(nil, nil, nil), ""},
+		{"single", Append(nil, a), "1 error: [required, field: name]"},
+		{"two", Append(Append(nil, a), nil, b), "2 errors: [required, field: name; too short, field: password, min: 8]"},
+		{"non-Multi first", Append(a, b), "2 errors: [required, field: name; too short, field: password, min: 8]"},
+		{"no errors to append", Append(a, nil), "required, field: name"},
+		{"shared fields", With(Append(nil, a, b), String("request_id", "r1")),
+			"2 errors: [required, field: name; too short, field: password, min: 8], request_id: r1"},
+		{"append to shared fields", Append(With(Append(nil, a), String("request_id", "r1")), b),
+			"2 errors: [required, field: name; too short, field: password, min: 8], request_id: r1"},
+		{"nested", Append(a, Append(nil, b)), "2 errors: [required, field: name; 1 error: [too short, field: password, min: 8]]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want == "" {
+				if tt.err != nil {
+					t.Fatalf("got %v, want nil", tt.err)
+				}
+				return
+			}
+			if got := tt.err.Error(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppend_doesNotModify(t *testing.T) {
+	first := Append(nil, New("a"))
+	second := Append(first, New("b"))
+	third := Append(first, New("c"))
+	if first.Error() != "1 error: [a]" || second.Error() != "2 errors: [a; b]" || third.Error() != "2 errors: [a; c]" {
+		t.Fatalf("got %q, %q, %q", first, second, third)
+	}
+}
+
+type multiTestError struct{ id int }
+
+func (e *multiTestError) Error() string { return "test error" }
+
+func TestMulti_unwrap(t *testing.T) {
+	errRequired := New("required")
+	err := With(Append(nil, With(errRequired, String("field", "name")), &multiTestError{id: 7}), String("request_id", "r1"))
+
+	if !errors.Is(err, errRequired) {
+		t.Errorf("errors.Is(err, errRequired) = false")
+	}
+	var te *multiTestError
+	if !errors.As(err, &te) || te.id != 7 {
+		t.Errorf("errors.As did not find the second error")
+	}
+	var m *Multi
+	if !errors.As(err, &m) || m.Len() != 2 {
+		t.Fatalf("errors.As(err, *Multi) = %v", m)
+	}
+	children := m.Errors()
+	if f, ok := Lookup(children[0], "field"); !ok || f.Value() != "name" {
+		t.Errorf("Lookup(children[0], field) = %v, %v", f, ok)
+	}
+	children[0] = nil
+	if m.Errors()[0] == nil {
+		t.Errorf("Errors does not return a copy")
+	}
+	if f, ok := Lookup(err, "request_id"); !ok || f.Value() != "r1" {
+		t.Errorf("Lookup(err, request_id) = %v, %v", f, ok)
+	}
+}
+
+func TestMulti_JSON(t *testing.T) {
+	err := With(Append(nil,
+		With(New("required", WithCode("required")), String("field", "name")),
+		New("too short"),
+	), String("request_id", "r1"))
+
+	tests := []struct {
+		name string
+		opts []JSONOption
+		want string
+	}{
+		{"nested", nil, `{"message":"2 errors","fields":{"request_id":"r1"},"errors":[` +
+			`{"message":"required","code":"required","fields":{"field":"name"}},{"message":"too short"}]}`},
+		{"flat", []JSONOption{WithJSONLayout(JSONFlat)}, `{"message":"2 errors","fields":{"request_id":"r1"},"errors":[` +
+			`{"message":"required","code":"required","fields":{"field":"name"}},{"message":"too short"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, jerr := JSON(err, tt.opts...)
+			if jerr != nil {
+				t.Fatalf("JSON error: %v", jerr)
+			}
+			if string(b) != tt.want {
+				t.Fatalf("got  %s\nwant %s", b, tt.want)
+			}
+		})
+	}
+
+	b, jerr := json.Marshal(Append(nil, New("a")))
+	if jerr != nil || string(b) != `{"message":"1 error","errors":[{"message":"a"}]}` {
+		t.Fatalf("json.Marshal(Multi) = %s, %v", b, jerr)
+	}
+}
@@ -0,0 +1,128 @@
+package errorc
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Error implements the error interface, so that a Namespace can be used as the target
+// of errors.Is: errors.Is(err, Namespace("storage")) reports whether err wraps an error
+// created by New, Namespace.NewError, or ErrorFactory under the "storage" namespace
+// or one of its children, such as "storage/s3".
+// It returns the namespace itself.
+func (n Namespace) Error() string {
+	return string(n)
+}
+
+// Contains reports whether err, or any error it wraps, was created by New,
+// Namespace.NewError, or ErrorFactory under this namespace or one of its children.
+// It is equivalent to errors.Is(err, n).
+func (n Namespace) Contains(err error) bool {
+	return errors.Is(err, n)
+}
+
+// NamespaceOf returns the namespace of the nearest error in the tree of errors wrapped
+// by err that was created under a namespace. It returns an empty Namespace if there is none.
+func NamespaceOf(err error) Namespace {
+	var ns Namespace
+	walk(err, func(err error) bool {
+		if s, ok := err.(*sentinel); ok && s.ns != "" {
+			ns = s.ns
+			return true
+		}
+		return false
+	})
+	return ns
+}
+
+// Child returns the namespace named name nested in n, for example
+// Namespace("storage").Child("s3") returns "storage/s3". Errors created under a child
+// namespace are members of all its ancestors: errors.Is(err, Namespace("storage"))
+// reports true for an error created under Namespace("storage").Child("s3").
+// The segments of the path are recorded by Child, so name is a single segment even if it
+// contains "/", and a namespace that is not built by Child or by nested WithNamespace
+// options, such as Namespace("api/v1"), has no parent.
+// If name is empty, Child returns n; if n is empty, Child returns Namespace(name).
+func (n Namespace) Child(name string) Namespace {
+	switch {
+	case name == "":
+		return n
+	case n == "":
+		return Namespace(name)
+	}
+	return join(n.path(), []string{name})
+}
+
+// Parent returns the namespace n is nested in, or an empty Namespace if n has no parent.
+func (n Namespace) Parent() Namespace {
+	path := n.path()
+	if len(path) < 2 {
+		return ""
+	}
+	parent := n[:len(n)-len(path[len(path)-1])-1]
+	if len(path) > 2 {
+		namespacePaths.LoadOrStore(parent, slices.Clip(path[:len(path)-1]))
+	}
+	return parent
+}
+
+// includes reports whether m is n or one of its descendants.
+func (n Namespace) includes(m Namespace) bool {
+	if n == "" || !strings.HasPrefix(string(m), string(n)) {
+		return false
+	}
+	if m == n {
+		return true
+	}
+	np, mp := n.path(), m.path()
+	return len(mp) > len(np) && slices.Equal(mp[:len(np)], np)
+}
+
+// namespacePaths maps the namespaces built by Child or by nested WithNamespace options to
+// their path segments, outermost first. The first path recorded for a namespace wins.
+var namespacePaths sync.Map // Namespace -> []string
+
+// path returns the segments of n, outermost first. A namespace that is not in
+// namespacePaths is a single segment.
+func (n Namespace) path() []string {
+	if n == "" {
+		return nil
+	}
+	if path, ok := namespacePaths.Load(n); ok {
+		return path.([]string)
+	}
+	return []string{string(n)}
+}
+
+// join returns the namespace with the segments of outer followed by those of inner,
+// separated by "/", and records its path.
+func join(outer, inner []string) Namespace {
+	path := make([]string, 0, len(outer)+len(inner))
+	path = append(append(path, outer...), inner...)
+	n := Namespace(strings.Join(path, "/"))
+	namespacePaths.LoadOrStore(n, path)
+	return n
+}
+
+// defaultNamespaceSeparator separates namespace segments in error messages by default.
+const defaultNamespaceSeparator = ": "
+
+// namespaceSeparator holds the separator set by SetNamespaceSeparator, if any.
+var namespaceSeparator atomic.Pointer[string]
+
+// SetNamespaceSeparator sets how the segments of hierarchical namespaces are separated
+// in error messages. The default is ": ", which renders Namespace("storage").Child("s3")
+// as "storage: s3: read_failed"; with "/" the same error renders as "storage/s3: read_failed".
+// The namespace and the message are always separated by ": ".
+// It affects errors created before the call too, so messages compared as strings, for
+// example in tests, change with it.
+func SetNamespaceSeparator(sep string) {
+	if sep == defaultNamespaceSeparator {
+		namespaceSeparator.Store(nil)
+		return
+	}
+	namespaceSeparator.Store(&sep)
+}
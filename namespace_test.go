@@ -0,0 +1,152 @@
+package errorc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNamespace_Is(t *testing.T) {
+	storage := Namespace("storage")
+	storageErr := ErrorFactory(storage)
+
+	tests := []struct {
+		name string
+		err  error
+		ns   Namespace
+		want bool
+	}{
+		{"NewError", storage.NewError("read_failed"), storage, true},
+		{"ErrorFactory", storageErr("read_failed"), storage, true},
+		{"New with WithNamespace", New("read_failed", WithNamespace("storage")), storage, true},
+		{"wrapped", With(fmt.Errorf("load: %w", storage.NewError("x")), String("k", "v")), storage, true},
+		{"joined", errors.Join(errors.New("other"), storage.NewError("x")), storage, true},
+		{"other namespace", Namespace("network").NewError("x"), storage, false},
+		{"no namespace", New("x"), storage, false},
+		{"same text, not namespaced", errors.New("storage: x"), storage, false},
+		{"empty namespace target", New("x"), Namespace(""), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.ns); got != tt.want {
+				t.Fatalf("errors.Is() = %v, want %v", got, tt.want)
+			}
+			if got := tt.ns.Contains(tt.err); got != tt.want {
+				t.Fatalf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamespaceOf(t *testing.T) {
+	if got := NamespaceOf(New("x")); got != "" {
+		t.Fatalf("NamespaceOf() = %q, want empty", got)
+	}
+	err := With(fmt.Errorf("load: %w", Namespace("storage").NewError("x")), String("k", "v"))
+	if got := NamespaceOf(err); got != "storage" {
+		t.Fatalf("NamespaceOf() = %q, want 'storage'", got)
+	}
+}
+
+func TestNamespace_Child(t *testing.T) {
+	storage := Namespace("storage")
+	s3 := storage.Child("s3")
+
+	if s3 != "storage/s3" {
+		t.Fatalf("Child() = %q, want 'storage/s3'", s3)
+	}
+	if got := storage.Child(""); got != storage {
+		t.Fatalf("Child(\"\") = %q, want %q", got, storage)
+	}
+	if got := Namespace("").Child("s3"); got != "s3" {
+		t.Fatalf("Namespace(\"\").Child() = %q, want 's3'", got)
+	}
+	if got := s3.Child("eu").Parent(); got != s3 {
+		t.Fatalf("Parent() = %q, want %q", got, s3)
+	}
+	if got := storage.Parent(); got != "" {
+		t.Fatalf("Parent() = %q, want empty", got)
+	}
+
+	err := With(s3.NewError("read_failed"), String("bucket", "b1"))
+	if got := err.Error(); got != "storage: s3: read_failed, bucket: b1" {
+		t.Fatalf("Error() = %q", got)
+	}
+	if got := NamespaceOf(err); got != s3 {
+		t.Fatalf("NamespaceOf() = %q, want %q", got, s3)
+	}
+
+	tests := []struct {
+		ns   Namespace
+		want bool
+	}{
+		{storage, true},
+		{s3, true},
+		{s3.Child("eu"), false},
+		{Namespace("stor"), false},
+		{Namespace("s3"), false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(err, tt.ns); got != tt.want {
+			t.Errorf("errors.Is(err, %q) = %v, want %v", tt.ns, got, tt.want)
+		}
+	}
+
+	stacked := New("read_failed", WithNamespace("storage"), WithNamespace("environment"))
+	if !errors.Is(stacked, Namespace("environment")) || !errors.Is(stacked, Namespace("environment/storage")) {
+		t.Fatalf("stacked namespaces should form a hierarchy")
+	}
+	if errors.Is(stacked, storage) {
+		t.Fatalf("a nested namespace is not a member of an unrelated root namespace")
+	}
+}
+
+func TestNamespace_slash(t *testing.T) {
+	literal := Namespace("slash_test/v1")
+	err := literal.NewError("read_failed")
+
+	if got := err.Error(); got != "slash_test/v1: read_failed" {
+		t.Fatalf("Error() = %q, want 'slash_test/v1: read_failed'", got)
+	}
+	if got := fmt.Sprintf("%#v", err); got != `errorc.New("read_failed", errorc.WithNamespace("slash_test/v1"))` {
+		t.Fatalf("%%#v = %s", got)
+	}
+	if got := literal.Parent(); got != "" {
+		t.Fatalf("Parent() = %q, want empty", got)
+	}
+	if !errors.Is(err, literal) || errors.Is(err, Namespace("slash_test")) {
+		t.Fatalf("a namespace containing '/' should be a single segment")
+	}
+
+	users := literal.Child("users")
+	err = users.NewError("read_failed")
+	if got := err.Error(); got != "slash_test/v1: users: read_failed" {
+		t.Fatalf("Error() = %q, want 'slash_test/v1: users: read_failed'", got)
+	}
+	if got := users.Parent(); got != literal {
+		t.Fatalf("Parent() = %q, want %q", got, literal)
+	}
+	if !errors.Is(err, literal) || !errors.Is(err, users) {
+		t.Fatalf("an error created under a child namespace should match its ancestors")
+	}
+}
+
+func TestSetNamespaceSeparator(t *testing.T) {
+	err := Namespace("storage").Child("s3").NewError("read_failed")
+	flat := Namespace("storage").NewError("read_failed")
+
+	SetNamespaceSeparator("/")
+	defer SetNamespaceSeparator(": ")
+
+	if got := err.Error(); got != "storage/s3: read_failed" {
+		t.Fatalf("Error() = %q, want 'storage/s3: read_failed'", got)
+	}
+	if got := flat.Error(); got != "storage: read_failed" {
+		t.Fatalf("Error() = %q, want 'storage: read_failed'", got)
+	}
+
+	SetNamespaceSeparator(": ")
+	if got := err.Error(); got != "storage: s3: read_failed" {
+		t.Fatalf("Error() = %q, want 'storage: s3: read_failed'", got)
+	}
+}
@@ -0,0 +1,143 @@
+package errorc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse splits an error message rendered with the default format, "message, key: value",
+// into the message and the fields attached by With. It is a shorthand for the Parse method
+// of the zero Renderer; see Renderer.Parse for the parsing rules.
+func Parse(s string) (message string, fields []Field, err error) {
+	var r Renderer
+	return r.Parse(s)
+}
+
+// Parse splits an error message rendered by r into the message and the fields attached
+// by With. The fields are returned with KindString values, since their types are not
+// represented in the message. Field.Value returns the value as rendered, unquoted.
+//
+// The message ends before the first field separator followed by a keyed field, that is
+// a key and a key-value separator. The rest of s is parsed as fields. This implies the
+// following ambiguities:
+//   - a message containing the field separator followed by text that looks like a keyed
+//     field is split there, like "failed, retry: later" when the message is
+//     "failed, retry: later" and there are no fields. If r.Quote is true, a double quote
+//     following the field separator in the message can start a quoted key as well;
+//   - fields with an empty key that precede the first keyed field are parsed as part of
+//     the message;
+//   - if the message is empty, s starts with the field separator.
+//
+// If r.Quote is false, the fields are split at every field separator and a key ends at the
+// first key-value separator, so values containing a field separator are split into several
+// fields, keys cannot contain the key-value separator, and control characters escaped with
+// r.Escape are not unescaped. A field with an empty key and an empty value renders as an
+// empty string, which is parsed as such.
+//
+// If r.Quote is true, the fields are parsed strictly: quoted keys and values are unquoted,
+// and other keys and values cannot contain whitespace, double quotes, or a separator.
+// Round-tripping is exact as long as the message does not contain a field separator
+// followed by a keyed field or a double quote, and the first field has a key. If the rest
+// of s after the message is not a valid list of fields, Parse returns an error.
+//
+// Parse returns an error if the field separator and the key-value separator are equal.
+func (r *Renderer) Parse(s string) (message string, fields []Field, err error) {
+	fs, kv := r.fieldSeparator(), r.keyValueSeparator()
+	if fs == kv {
+		return "", nil, errors.New("errorc: the field and key-value separators of the Renderer are equal")
+	}
+
+	// Separators can overlap the end of the message, so every offset is a candidate.
+	for i := 0; ; i++ {
+		j := strings.Index(s[i:], fs)
+		if j < 0 {
+			return s, nil, nil
+		}
+		i += j
+		rest := s[i+len(fs):]
+		if !r.keyedField(rest) {
+			continue
+		}
+		if fields, err = r.parseFields(rest); err != nil {
+			return "", nil, fmt.Errorf("errorc: cannot parse fields at offset %d: %w", i+len(fs), err)
+		}
+		return s[:i], fields, nil
+	}
+}
+
+// keyedField reports whether s starts with a non-empty key followed by the key-value separator.
+func (r *Renderer) keyedField(s string) bool {
+	if !r.Quote {
+		piece, _, _ := strings.Cut(s, r.fieldSeparator())
+		k := strings.Index(piece, r.keyValueSeparator())
+		return k > 0
+	}
+	key, rest, err := r.token(s)
+	return err == nil && key != "" && strings.HasPrefix(rest, r.keyValueSeparator())
+}
+
+// parseFields parses a list of fields separated by the field separator.
+func (r *Renderer) parseFields(s string) ([]Field, error) {
+	fs, kv := r.fieldSeparator(), r.keyValueSeparator()
+	var fields []Field
+	if !r.Quote {
+		for _, piece := range strings.Split(s, fs) {
+			if key, value, ok := strings.Cut(piece, kv); ok {
+				fields = append(fields, Field{key: key, kind: KindString, str: value})
+			} else {
+				fields = append(fields, Field{kind: KindString, str: piece})
+			}
+		}
+		return fields, nil
+	}
+
+	for {
+		first, rest, err := r.token(s)
+		if err != nil {
+			return nil, err
+		}
+		f := Field{kind: KindString, str: first}
+		if after, ok := strings.CutPrefix(rest, kv); ok {
+			f.key = first
+			if f.str, rest, err = r.token(after); err != nil {
+				return nil, err
+			}
+		}
+		fields = append(fields, f)
+
+		if rest == "" {
+			return fields, nil
+		}
+		var ok bool
+		if s, ok = strings.CutPrefix(rest, fs); !ok {
+			return nil, fmt.Errorf("expected %q before %q", fs, rest)
+		}
+	}
+}
+
+// token reads a key or a value rendered with r.Quote set from the start of s.
+// It returns the unquoted token and the rest of s.
+func (r *Renderer) token(s string) (token, rest string, err error) {
+	if strings.HasPrefix(s, `"`) {
+		q, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid quoted string %q", s)
+		}
+		token, err = strconv.Unquote(q)
+		return token, s[len(q):], err
+	}
+
+	end := len(s)
+	for _, sep := range [...]string{r.fieldSeparator(), r.keyValueSeparator()} {
+		if k := strings.Index(s, sep); k >= 0 && k < end {
+			end = k
+		}
+	}
+	token = s[:end]
+	if token == "" || r.needsQuote([]byte(token)) {
+		return "", "", fmt.Errorf("%q must be quoted", token)
+	}
+	return token, s[end:], nil
+}
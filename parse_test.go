@@ -0,0 +1,102 @@
+package errorc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type kv struct{ key, value string }
+	tests := []struct {
+		name    string
+		r       Renderer
+		in      string
+		message string
+		fields  []kv
+	}{
+		{"no fields", Renderer{}, "request failed", "request failed", nil},
+		{"message with separator", Renderer{}, "storage: not found, retry later", "storage: not found, retry later", nil},
+		{"default", Renderer{}, "request failed, path: /a b, value only, attempt: 2",
+			"request failed", []kv{{"path", "/a b"}, {"", "value only"}, {"attempt", "2"}}},
+		{"empty value", Renderer{}, "request failed, empty: , k: v", "request failed", []kv{{"empty", ""}, {"k", "v"}}},
+		{"empty message", Renderer{}, ", k: v", "", []kv{{"k", "v"}}},
+		{"value with key-value separator", Renderer{}, "failed, url: http://x", "failed", []kv{{"url", "http://x"}}},
+		{"value with field separator", Renderer{}, "failed, note: x, y", "failed", []kv{{"note", "x"}, {"", "y"}}},
+		{"separators", Renderer{FieldSeparator: "; ", KeyValueSeparator: "="}, "request failed; a=1; b=x=y",
+			"request failed", []kv{{"a", "1"}, {"b", "x=y"}}},
+		{"quote", Renderer{Quote: true}, `request failed, path: "/a b", note: "x, k: v", "value only", "multi line": "a\nb"`,
+			"request failed", []kv{{"path", "/a b"}, {"note", "x, k: v"}, {"", "value only"}, {"multi line", "a\nb"}}},
+		{"logfmt", Logfmt, `request failed path="/a b" status=503 empty=""`,
+			"request failed", []kv{{"path", "/a b"}, {"status", "503"}, {"empty", ""}}},
+		{"logfmt message with spaces", Logfmt, `user not found id=42`, "user not found", []kv{{"id", "42"}}},
+		{"logfmt no fields", Logfmt, `user not found`, "user not found", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, fields, err := tt.r.Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.in, err)
+			}
+			if message != tt.message {
+				t.Errorf("message = %q, want %q", message, tt.message)
+			}
+			if len(fields) != len(tt.fields) {
+				t.Fatalf("got %d fields, want %d: %v", len(fields), len(tt.fields), fields)
+			}
+			for i, f := range fields {
+				if f.Key() != tt.fields[i].key || f.Value() != tt.fields[i].value || f.Kind() != KindString {
+					t.Errorf("field %d = %q: %q (%v), want %q: %q", i, f.Key(), f.Value(), f.Kind(), tt.fields[i].key, tt.fields[i].value)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Renderer
+		in   string
+		want string
+	}{
+		{"equal separators", Renderer{FieldSeparator: " ", KeyValueSeparator: " "}, "a b c", "separators"},
+		{"unterminated quote", Logfmt, `failed k="v`, "offset 7"},
+		{"unquoted space", Renderer{Quote: true}, `failed, k: a b`, "must be quoted"},
+		{"missing separator", Logfmt, `failed k="v"x`, "expected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.r.Parse(tt.in)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Parse(%q) error = %v, want it to contain %q", tt.in, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_roundTrip(t *testing.T) {
+	fields := []field{String("path", "/a b"), Int("status", 503), String("note", `x, k: "v"`), String("", "value only")}
+	for _, r := range []Renderer{{Quote: true}, {Quote: true, Escape: true}, Logfmt} {
+		err := r.With(New("request failed"), fields...)
+		message, got, perr := r.Parse(err.Error())
+		if perr != nil {
+			t.Fatalf("%+v: Parse(%q) error: %v", r, err.Error(), perr)
+		}
+		if message != "request failed" || len(got) != len(fields) {
+			t.Fatalf("%+v: Parse(%q) = %q, %v", r, err.Error(), message, got)
+		}
+		for i, f := range got {
+			if want := fields[i].Field(); f.Key() != want.Key() || f.Value() != want.Value() {
+				t.Errorf("%+v: field %d = %q: %q, want %q: %q", r, i, f.Key(), f.Value(), want.Key(), want.Value())
+			}
+		}
+	}
+}
+
+func TestParse_packageFunc(t *testing.T) {
+	message, fields, err := Parse(With(New("not found"), String("id", "1")).Error())
+	if err != nil || message != "not found" || len(fields) != 1 || fields[0].Key() != "id" || fields[0].Value() != "1" {
+		t.Fatalf("Parse = %q, %v, %v", message, fields, err)
+	}
+}
@@ -0,0 +1,218 @@
+package errorc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// Sensitivity classifies the value of a Field.
+type Sensitivity uint8
+
+// Field sensitivities, from the least to the most sensitive.
+const (
+	// SensitivityNone is the sensitivity of fields that can be rendered verbatim.
+	SensitivityNone Sensitivity = iota
+	// SensitivitySensitive is the sensitivity of fields created with Sensitive, for
+	// example personal data like email addresses.
+	SensitivitySensitive
+	// SensitivitySecret is the sensitivity of fields created with Secret, for example
+	// credentials and tokens.
+	SensitivitySecret
+)
+
+// Sensitive marks a field as holding sensitive data. Its value is rendered according to
+// the Sensitive member of the RedactionPolicy of each output, by default masked:
+//
+//	err := With(ErrInvalidInput, Sensitive(String("email", email)))
+//	// invalid input, email: ***
+//
+// If f is nil it returns nil so that it will be ignored by With(). Fields that are not
+// rendered, like those created by Stack and Classify, are returned unchanged.
+func Sensitive(f field) field {
+	return classify(f, SensitivitySensitive)
+}
+
+// Secret marks a field as holding secret data. Its value is rendered according to
+// the Secret member of the RedactionPolicy of each output, by default omitted with its key.
+// If f is nil it returns nil so that it will be ignored by With(). Fields that are not
+// rendered, like those created by Stack and Classify, are returned unchanged.
+func Secret(f field) field {
+	return classify(f, SensitivitySecret)
+}
+
+func classify(f field, s Sensitivity) field {
+	if f == nil || f().marker() {
+		return f
+	}
+	return func() Field {
+		sf := f()
+		if sf.sens < s {
+			sf.sens = s
+		}
+		return sf
+	}
+}
+
+// Redaction selects how the value of a sensitive field is rendered.
+type Redaction uint8
+
+const (
+	// RedactNone renders the value verbatim.
+	RedactNone Redaction = iota
+	// RedactMask replaces the value with "***".
+	RedactMask
+	// RedactHash replaces the value with "sha256:" followed by the first 16 hexadecimal
+	// digits of the SHA-256 hash of its rendered representation, so that occurrences of
+	// the same value can be correlated. Hashes of values with few possible inputs, like
+	// phone numbers, can be reversed by brute force.
+	RedactHash
+	// RedactOmit leaves the field out, key included.
+	RedactOmit
+)
+
+// mask is the value of a field redacted with RedactMask.
+const mask = "***"
+
+// RedactionPolicy selects the redaction of each sensitivity. Fields with SensitivityNone
+// are always rendered verbatim.
+type RedactionPolicy struct {
+	Sensitive Redaction
+	Secret    Redaction
+}
+
+// DefaultRedactionPolicy is the policy of every output unless set otherwise with
+// SetRedactionPolicy: sensitive values are masked and secret fields are omitted.
+var DefaultRedactionPolicy = RedactionPolicy{Sensitive: RedactMask, Secret: RedactOmit}
+
+// Output is a representation of errors a RedactionPolicy applies to.
+type Output uint8
+
+const (
+	// OutputText is the error message returned by Error and the fmt verbs.
+	OutputText Output = iota
+	// OutputJSON is the encoding produced by JSON and MarshalJSON.
+	OutputJSON
+	// OutputSlog is the representation produced by LogValue and Attrs.
+	OutputSlog
+	// OutputExport is the policy other exporters, like the httperr and grpcerr packages,
+	// apply to the fields returned by Fields.
+	OutputExport
+
+	outputCount
+)
+
+// redactionPolicies holds the policies set with SetRedactionPolicy, indexed by Output.
+var redactionPolicies [outputCount]atomic.Pointer[RedactionPolicy]
+
+// SetRedactionPolicy sets the redaction policy of an output. The policy is read each time
+// an error is rendered or exported, so it applies to errors created before the call too.
+// Unknown outputs are ignored.
+func SetRedactionPolicy(out Output, p RedactionPolicy) {
+	if out < outputCount {
+		redactionPolicies[out].Store(&p)
+	}
+}
+
+// RedactionPolicyOf returns the redaction policy of an output.
+func RedactionPolicyOf(out Output) RedactionPolicy {
+	if out < outputCount {
+		if p := redactionPolicies[out].Load(); p != nil {
+			return *p
+		}
+	}
+	return DefaultRedactionPolicy
+}
+
+// ExportPolicy selects the fields of an error that exporters, like the httperr and grpcerr
+// packages, send outside the process. The zero value exports every field, redacted with the
+// policy of OutputExport.
+type ExportPolicy struct {
+	omit   func(Field) bool
+	policy *RedactionPolicy
+}
+
+// Omit leaves out the fields for which omit returns true, for example because they hold
+// internal data. Calling Omit several times leaves out the fields reported by any of the
+// functions.
+func (x *ExportPolicy) Omit(omit func(f Field) bool) {
+	if prev := x.omit; prev != nil {
+		x.omit = func(f Field) bool { return prev(f) || omit(f) }
+		return
+	}
+	x.omit = omit
+}
+
+// SetRedactionPolicy sets the redaction policy applied to the fields marked with Sensitive
+// and Secret, instead of the policy of OutputExport.
+func (x *ExportPolicy) SetRedactionPolicy(p RedactionPolicy) {
+	x.policy = &p
+}
+
+// Fields returns the fields of err like Fields, without the fields left out by Omit or by
+// the redaction policy, and with the other sensitive and secret values redacted.
+func (x *ExportPolicy) Fields(err error) []Field {
+	fields := Fields(err)
+	if len(fields) == 0 {
+		return nil
+	}
+	p := x.policy
+	if p == nil {
+		policy := RedactionPolicyOf(OutputExport)
+		p = &policy
+	}
+
+	exported := fields[:0]
+	for _, f := range fields {
+		if x.omit != nil && x.omit(f) {
+			continue
+		}
+		if f, ok := f.Redact(*p); ok {
+			exported = append(exported, f)
+		}
+	}
+	if len(exported) == 0 {
+		return nil
+	}
+	return exported
+}
+
+// Sensitivity returns the sensitivity of the field.
+func (s Field) Sensitivity() Sensitivity {
+	return s.sens
+}
+
+// Redact applies policy p to the field. It returns the field unchanged if its value is
+// rendered verbatim, a field of kind KindString holding the masked or hashed value,
+// or false if the field is omitted.
+func (s Field) Redact(p RedactionPolicy) (Field, bool) {
+	var r Redaction
+	switch s.sens {
+	case SensitivityNone:
+		return s, true
+	case SensitivitySensitive:
+		r = p.Sensitive
+	default:
+		r = p.Secret
+	}
+
+	switch r {
+	case RedactNone:
+		return s, true
+	case RedactMask:
+		return Field{key: s.key, kind: KindString, sens: s.sens, str: mask}, true
+	case RedactHash:
+		sum := sha256.Sum256(s.appendValue(nil))
+		return Field{key: s.key, kind: KindString, sens: s.sens, str: "sha256:" + hex.EncodeToString(sum[:8])}, true
+	default:
+		return Field{}, false
+	}
+}
+
+// redactFor applies the redaction policy of out to the field.
+func (s Field) redactFor(out Output) (Field, bool) {
+	if s.sens == SensitivityNone {
+		return s, true
+	}
+	return s.Redact(RedactionPolicyOf(out))
+}
@@ -0,0 +1,180 @@
+package errorc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// setRedactionPolicy sets the policy of out for the duration of the test.
+func setRedactionPolicy(t *testing.T, out Output, p RedactionPolicy) {
+	t.Helper()
+	prev := redactionPolicies[out].Load()
+	SetRedactionPolicy(out, p)
+	t.Cleanup(func() { redactionPolicies[out].Store(prev) })
+}
+
+func TestSensitive(t *testing.T) {
+	if Sensitive(nil) != nil || Secret(nil) != nil || Secret(Error("e", nil)) != nil {
+		t.Fatalf("Sensitive and Secret of a nil field are not nil")
+	}
+	if s := Sensitive(String("k", "v")).Field().Sensitivity(); s != SensitivitySensitive {
+		t.Fatalf("Sensitivity() = %v, want %v", s, SensitivitySensitive)
+	}
+	if s := Sensitive(Secret(String("k", "v"))).Field().Sensitivity(); s != SensitivitySecret {
+		t.Fatalf("Sensitivity() = %v, want %v: a field cannot be made less sensitive", s, SensitivitySecret)
+	}
+	if s := String("k", "v").Field().Sensitivity(); s != SensitivityNone {
+		t.Fatalf("Sensitivity() = %v, want %v", s, SensitivityNone)
+	}
+
+	err := With(New("base"), Sensitive(Stack()), Secret(Classify(Permanent)))
+	if StackTrace(err) == nil || ClassOf(err) != Permanent {
+		t.Fatalf("Sensitive and Secret dropped a marker: StackTrace = %v, ClassOf = %v", StackTrace(err), ClassOf(err))
+	}
+	if got := err.Error(); got != "base" {
+		t.Fatalf("Error() = %q, want 'base'", got)
+	}
+}
+
+func TestField_Redact(t *testing.T) {
+	f := Sensitive(Int("id", 42)).Field()
+	tests := []struct {
+		name   string
+		policy RedactionPolicy
+		want   string
+		ok     bool
+	}{
+		{"none", RedactionPolicy{Sensitive: RedactNone}, "42", true},
+		{"mask", RedactionPolicy{Sensitive: RedactMask}, "***", true},
+		{"hash", RedactionPolicy{Sensitive: RedactHash}, "sha256:73475cb40a568e8d", true},
+		{"omit", RedactionPolicy{Sensitive: RedactOmit}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := f.Redact(tt.policy)
+			if ok != tt.ok {
+				t.Fatalf("Redact() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && (got.Value() != tt.want || got.Key() != "id" || got.Sensitivity() != SensitivitySensitive) {
+				t.Fatalf("Redact() = %v %q %v, want %q", got.Key(), got.Value(), got.Sensitivity(), tt.want)
+			}
+		})
+	}
+
+	plain := Int("id", 42).Field()
+	if got, ok := plain.Redact(RedactionPolicy{Sensitive: RedactOmit, Secret: RedactOmit}); !ok || got.Kind() != KindInt64 {
+		t.Fatalf("Redact() changed a field that is not sensitive")
+	}
+}
+
+func TestRedaction_outputs(t *testing.T) {
+	err := With(New("login failed"),
+		String("user", "u1"),
+		Sensitive(String("email", "a@example.com")),
+		Secret(String("token", "t0k3n")),
+	)
+
+	if want := "login failed, user: u1, email: ***"; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+	if got := fmt.Sprintf("%+v", err); strings.Contains(got, "a@example.com") || strings.Contains(got, "t0k3n") {
+		t.Fatalf("%%+v = %q leaks a sensitive value", got)
+	}
+	want := `errorc.With(errorc.New("login failed"), errorc.String("user", "u1"), errorc.Sensitive(errorc.String("email", "***")))`
+	if got := fmt.Sprintf("%#v", err); got != want {
+		t.Fatalf("%%#v = %s, want %s", got, want)
+	}
+
+	b, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	if want := `{"message":"login failed","fields":{"user":"u1","email":"***"}}`; string(b) != want {
+		t.Fatalf("json.Marshal() = %s, want %s", b, want)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("x", slog.Any("error", err))
+	if got := buf.String(); !strings.Contains(got, "error.email=***") || strings.Contains(got, "token") {
+		t.Fatalf("slog output = %q", got)
+	}
+	if attrs := Attrs(err); len(attrs) != 2 || attrs[1].Value.String() != "***" {
+		t.Fatalf("Attrs() = %v", attrs)
+	}
+
+	// Fields and Lookup return the original values for programmatic access.
+	if f, ok := Lookup(err, "token"); !ok || f.Value() != "t0k3n" {
+		t.Fatalf("Lookup() = %v, %v", f, ok)
+	}
+}
+
+func TestSetRedactionPolicy(t *testing.T) {
+	err := With(New("login failed"), Sensitive(String("email", "a@example.com")), Secret(String("token", "t0k3n")))
+
+	setRedactionPolicy(t, OutputText, RedactionPolicy{Sensitive: RedactNone, Secret: RedactHash})
+	if want := "login failed, email: a@example.com, token: sha256:b81c829ac55e858e"; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+	if p := RedactionPolicyOf(OutputJSON); p != DefaultRedactionPolicy {
+		t.Fatalf("RedactionPolicyOf(OutputJSON) = %v, want the default policy", p)
+	}
+
+	b, jerr := JSON(err, WithJSONRedactionPolicy(RedactionPolicy{Sensitive: RedactOmit, Secret: RedactMask}))
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	if want := `{"message":"login failed","fields":{"token":"***"}}`; string(b) != want {
+		t.Fatalf("JSON() = %s, want %s", b, want)
+	}
+
+	SetRedactionPolicy(outputCount, RedactionPolicy{})
+	if p := RedactionPolicyOf(outputCount); p != DefaultRedactionPolicy {
+		t.Fatalf("RedactionPolicyOf(unknown) = %v, want the default policy", p)
+	}
+}
+
+func TestExportPolicy(t *testing.T) {
+	err := With(New("login failed"),
+		String("user", "u1"),
+		String("internal", "db1"),
+		Sensitive(String("email", "a@example.com")),
+		Secret(String("token", "t0k3n")),
+	)
+
+	var x ExportPolicy
+	if got, want := fieldStrings(x.Fields(err)), "user: u1, internal: db1, email: ***"; got != want {
+		t.Fatalf("zero ExportPolicy: Fields() = %s, want %s", got, want)
+	}
+
+	x.Omit(func(f Field) bool { return f.Key() == "internal" })
+	x.Omit(func(f Field) bool { return f.Key() == "user" })
+	x.SetRedactionPolicy(RedactionPolicy{Sensitive: RedactNone, Secret: RedactMask})
+	if got, want := fieldStrings(x.Fields(err)), "email: a@example.com, token: ***"; got != want {
+		t.Fatalf("Fields() = %s, want %s", got, want)
+	}
+	if x.Fields(New("plain")) != nil {
+		t.Fatalf("Fields() of an error without fields is not nil")
+	}
+}
+
+// fieldStrings renders fields as "key: value" separated by ", ".
+func fieldStrings(fields []Field) string {
+	var b []byte
+	for i := range fields {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = fields[i].appendBytes(b)
+	}
+	return string(b)
+}
+
+func TestRedaction_emptyMessage(t *testing.T) {
+	if got := With(New(""), Secret(String("token", "t"))).Error(); got != "" {
+		t.Fatalf("Error() = %q, want an empty message", got)
+	}
+}
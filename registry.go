@@ -0,0 +1,150 @@
+package errorc
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Entry describes an error recorded by a Registry.
+type Entry struct {
+	Namespace   Namespace
+	Code        string
+	Message     string
+	Description string
+	// Metadata holds the values attached with WithMetadata. It must not be modified.
+	Metadata map[string]any
+	// Err is the recorded error, as returned by New.
+	Err error
+}
+
+// Registry is a catalog of errors declared under its namespaces.
+//
+// A Registry is opt-in: namespaces are bound to it with Registry.Namespace, and every
+// error created afterwards by New, Namespace.NewError, or ErrorFactory under such
+// a namespace or one of its children is recorded. Errors are usually declared as
+// package-level variables, so the catalog is complete once package initialization is done.
+//
+// Two coded errors with the same namespace and code are duplicates. By default, recording
+// a duplicate panics, which makes a program with duplicate codes fail at initialization;
+// use OnDuplicate to report duplicates instead.
+type Registry struct {
+	onDuplicate func(existing, duplicate Entry)
+
+	mu      sync.RWMutex
+	entries []Entry
+	byCode  map[codeKey]int // index in entries
+}
+
+type codeKey struct {
+	ns   Namespace
+	code string
+}
+
+// RegistryOption configures a Registry created by NewRegistry.
+type RegistryOption func(*Registry)
+
+// OnDuplicate sets the function called instead of panicking when an error with the same
+// namespace and code as an already recorded one is created. The duplicate is not recorded.
+func OnDuplicate(fn func(existing, duplicate Entry)) RegistryOption {
+	return func(r *Registry) {
+		r.onDuplicate = fn
+	}
+}
+
+// Unrecorded prevents the error created by New from being recorded by the Registry its
+// namespace is bound to. It is meant for errors reconstructed from another process, which
+// match the declared error with errors.Is but would otherwise be reported as duplicates.
+func Unrecorded() Option {
+	return func(o *options) {
+		o.unrecorded = true
+	}
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{byCode: make(map[codeKey]int)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// registries maps namespaces to the Registry they are bound to.
+var registries sync.Map // map[Namespace]*Registry
+
+// Namespace binds ns and its children to the registry and returns ns, so that errors
+// declared under it are recorded:
+//
+//	var catalog = errorc.NewRegistry()
+//	var storage = catalog.Namespace("storage")
+//	var ErrReadFailed = storage.NewError("read failed", errorc.WithCode("read_failed"))
+//
+// A namespace is bound to at most one registry; binding it again replaces the previous binding.
+// Errors created under ns before the call are not recorded.
+func (r *Registry) Namespace(ns Namespace) Namespace {
+	if ns != "" {
+		registries.Store(ns, r)
+	}
+	return ns
+}
+
+// Entries returns the recorded errors in the order they were created.
+func (r *Registry) Entries() []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]Entry, len(r.entries))
+	copy(entries, r.entries)
+	return entries
+}
+
+// Lookup returns the recorded error with the given namespace and code.
+func (r *Registry) Lookup(ns Namespace, code string) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.byCode[codeKey{ns, code}]
+	if !ok {
+		return Entry{}, false
+	}
+	return r.entries[i], true
+}
+
+// add records e, checking its code for duplicates.
+func (r *Registry) add(e *sentinel, description string) {
+	entry := Entry{
+		Namespace:   e.ns,
+		Code:        e.code,
+		Message:     e.msg,
+		Description: description,
+		Metadata:    e.metadata,
+		Err:         e,
+	}
+
+	r.mu.Lock()
+	if e.code != "" {
+		key := codeKey{e.ns, e.code}
+		if i, ok := r.byCode[key]; ok {
+			existing := r.entries[i]
+			r.mu.Unlock()
+			if r.onDuplicate == nil {
+				panic(fmt.Sprintf("errorc: duplicate error code %q in namespace %q", e.code, e.ns))
+			}
+			r.onDuplicate(existing, entry)
+			return
+		}
+		r.byCode[key] = len(r.entries)
+	}
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+}
+
+// registryOf returns the registry ns or its nearest ancestor is bound to.
+func registryOf(ns Namespace) *Registry {
+	for ; ns != ""; ns = ns.Parent() {
+		if r, ok := registries.Load(ns); ok {
+			return r.(*Registry)
+		}
+	}
+	return nil
+}
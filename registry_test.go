@@ -0,0 +1,108 @@
+package errorc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	ns := r.Namespace("registry_test")
+
+	errRead := ns.NewError("read failed",
+		WithCode("read_failed"),
+		WithDescription("The object could not be read."),
+		WithMetadata("retryable", true),
+	)
+	errWrite := ErrorFactory(ns.Child("s3"))("write_failed")
+	New("not recorded", WithNamespace("registry_test_other"), WithCode("read_failed"))
+
+	entries := r.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Entries() returned %d entries, want 2", len(entries))
+	}
+
+	e := entries[0]
+	if e.Namespace != ns || e.Code != "read_failed" || e.Message != "read failed" ||
+		e.Description != "The object could not be read." || e.Metadata["retryable"] != true || e.Err != errRead {
+		t.Fatalf("Entries()[0] = %+v", e)
+	}
+	if e := entries[1]; e.Namespace != "registry_test/s3" || e.Code != "" || e.Message != "write_failed" || e.Err != errWrite {
+		t.Fatalf("Entries()[1] = %+v", e)
+	}
+
+	got, ok := r.Lookup(ns, "read_failed")
+	if !ok || got.Err != errRead {
+		t.Fatalf("Lookup() = %+v, %v", got, ok)
+	}
+	if _, ok := r.Lookup("registry_test_other", "read_failed"); ok {
+		t.Fatalf("Lookup() found an error from an unbound namespace")
+	}
+}
+
+func TestRegistry_duplicates(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		r := NewRegistry()
+		ns := r.Namespace("registry_test_panic")
+		ns.NewError("a", WithCode("dup"))
+
+		defer func() {
+			want := `errorc: duplicate error code "dup" in namespace "registry_test_panic"`
+			if got := recover(); got != want {
+				t.Fatalf("recover() = %v, want %q", got, want)
+			}
+		}()
+		ns.NewError("b", WithCode("dup"))
+	})
+
+	t.Run("report", func(t *testing.T) {
+		var reported []string
+		r := NewRegistry(OnDuplicate(func(existing, duplicate Entry) {
+			reported = append(reported, existing.Message+"/"+duplicate.Message)
+		}))
+		ns := r.Namespace("registry_test_report")
+		ns.NewError("a", WithCode("dup"))
+		ns.NewError("b", WithCode("dup"))
+		ns.NewError("c")
+		ns.NewError("c")
+
+		if len(reported) != 1 || reported[0] != "a/b" {
+			t.Fatalf("reported = %v, want [a/b]", reported)
+		}
+		if got := len(r.Entries()); got != 3 {
+			t.Fatalf("Entries() returned %d entries, want 3", got)
+		}
+	})
+}
+
+func TestUnrecorded(t *testing.T) {
+	r := NewRegistry()
+	ns := r.Namespace("registry_test_unrecorded")
+	declared := ns.NewError("read failed", WithCode("read_failed"))
+	received := ns.NewError("read failed", WithCode("read_failed"), Unrecorded())
+
+	if got := len(r.Entries()); got != 1 {
+		t.Fatalf("Entries() returned %d entries, want 1", got)
+	}
+	if !errors.Is(received, declared) {
+		t.Fatalf("errors.Is(received, declared) = false, want true")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	inner := New("not found", WithMetadata("status", 404))
+	outer := New("gone", WithMetadata("status", 410), WithMetadata("status", 411))
+
+	if v, ok := Metadata(With(inner, String("k", "v")), "status"); !ok || v != 404 {
+		t.Fatalf("Metadata() = %v, %v, want 404, true", v, ok)
+	}
+	if v, ok := Metadata(errors.Join(outer, inner), "status"); !ok || v != 411 {
+		t.Fatalf("Metadata() = %v, %v, want 411, true", v, ok)
+	}
+	if _, ok := Metadata(inner, "missing"); ok {
+		t.Fatalf("Metadata() found a missing key")
+	}
+	if _, ok := Metadata(errors.New("plain"), "status"); ok {
+		t.Fatalf("Metadata() found a key on a plain error")
+	}
+}
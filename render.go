@@ -0,0 +1,186 @@
+package errorc
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// Renderer configures how the fields attached by With are rendered in error messages.
+// The zero value renders today's default format: "message, key: value, key: value".
+//
+// A Renderer is selected globally with SetRenderer or for a single With call with
+// Renderer.With. It must not be modified once in use.
+type Renderer struct {
+	// FieldSeparator separates the message from the first field and the fields from
+	// each other. An empty separator selects the default, ", ".
+	FieldSeparator string
+	// KeyValueSeparator separates a key from its value. An empty separator selects
+	// the default, ": ".
+	KeyValueSeparator string
+	// Quote renders keys and values as double-quoted Go strings, using strconv.Quote, if
+	// they are empty, contain whitespace, control characters, double quotes, or one of
+	// the separators. Quoting makes every rendered field unambiguous.
+	Quote bool
+	// Escape replaces the control characters in keys and values that are not quoted with
+	// Go escape sequences, such as \n and \x00, so that the message fits on one line.
+	Escape bool
+}
+
+// Logfmt is a Renderer producing logfmt-style fields: "message key=value key2="a b"".
+var Logfmt = Renderer{FieldSeparator: " ", KeyValueSeparator: "=", Quote: true}
+
+const (
+	defaultFieldSeparator    = ", "
+	defaultKeyValueSeparator = ": "
+)
+
+// renderer holds the Renderer set by SetRenderer, if any.
+var renderer atomic.Pointer[Renderer]
+
+// SetRenderer sets the Renderer used by the errors returned by With. Errors returned by
+// Renderer.With keep their own Renderer. It affects errors created before the call too,
+// since messages are rendered when Error is called. A library that needs a fixed format
+// should use its own Renderer instead.
+func SetRenderer(r Renderer) {
+	if r == (Renderer{}) {
+		renderer.Store(nil)
+		return
+	}
+	renderer.Store(&r)
+}
+
+// With works like the package-level With, rendering the fields of the returned error
+// with r regardless of SetRenderer.
+func (r *Renderer) With(err error, fields ...field) error {
+	e := &errorWithFields{e: err, r: r}
+	if !e.add(fields) {
+		return err
+	}
+	return e
+}
+
+// renderer returns the Renderer of e, or nil for the default format.
+func (e *errorWithFields) renderer() *Renderer {
+	if e.r != nil {
+		return e.r
+	}
+	return renderer.Load()
+}
+
+// appendField appends a field rendered by e's Renderer to b.
+func (e *errorWithFields) appendField(b []byte, f *Field) []byte {
+	if r := e.renderer(); r != nil {
+		return r.appendField(b, f)
+	}
+	return f.appendBytes(b)
+}
+
+// render appends the visible fields to the message b and returns the result.
+func (r *Renderer) render(b []byte, fields []field) string {
+	sep := r.fieldSeparator()
+	for _, f := range fields {
+		sf := f.get()
+		sf, ok := sf.redactFor(OutputText)
+		if !ok {
+			continue
+		}
+		b = append(b, sep...)
+		b = r.appendField(b, &sf)
+	}
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(&b[0], len(b))
+}
+
+func (r *Renderer) fieldSeparator() string {
+	if r.FieldSeparator == "" {
+		return defaultFieldSeparator
+	}
+	return r.FieldSeparator
+}
+
+func (r *Renderer) keyValueSeparator() string {
+	if r.KeyValueSeparator == "" {
+		return defaultKeyValueSeparator
+	}
+	return r.KeyValueSeparator
+}
+
+// appendField appends the field to b as key, separator, and value, or as the value alone
+// if the key is empty.
+func (r *Renderer) appendField(b []byte, f *Field) []byte {
+	if f.key != "" {
+		b = r.appendText(b, f.key)
+		b = append(b, r.keyValueSeparator()...)
+	}
+	if !r.Quote && !r.Escape {
+		return f.appendValue(b)
+	}
+	start := len(b)
+	b = f.appendValue(b)
+	// The value is re-appended only when it needs quoting or escaping.
+	if v := b[start:]; r.needsQuote(v) || (r.Escape && hasControl(v)) {
+		return r.appendText(b[:start], string(v))
+	}
+	return b
+}
+
+// appendText appends s to b, quoted or escaped as configured.
+func (r *Renderer) appendText(b []byte, s string) []byte {
+	switch {
+	case r.needsQuote(unsafe.Slice(unsafe.StringData(s), len(s))):
+		return strconv.AppendQuote(b, s)
+	case r.Escape:
+		return appendEscaped(b, s)
+	default:
+		return append(b, s...)
+	}
+}
+
+// needsQuote reports whether Quote is set and v must be quoted.
+func (r *Renderer) needsQuote(v []byte) bool {
+	if !r.Quote {
+		return false
+	}
+	if len(v) == 0 {
+		return true
+	}
+	s := unsafe.String(&v[0], len(v))
+	if strings.Contains(s, r.fieldSeparator()) || strings.Contains(s, r.keyValueSeparator()) {
+		return true
+	}
+	for _, c := range s {
+		if c == '"' || c == utf8.RuneError || unicode.IsSpace(c) || unicode.IsControl(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasControl(v []byte) bool {
+	for _, c := range string(v) {
+		if unicode.IsControl(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendEscaped appends s to b, replacing control characters with Go escape sequences.
+func appendEscaped(b []byte, s string) []byte {
+	for _, c := range s {
+		if !unicode.IsControl(c) {
+			b = utf8.AppendRune(b, c)
+			continue
+		}
+		// strconv.QuoteRune returns the escape sequence between single quotes.
+		q := strconv.QuoteRune(c)
+		b = append(b, q[1:len(q)-1]...)
+	}
+	return b
+}
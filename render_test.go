@@ -0,0 +1,89 @@
+package errorc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// setRenderer sets the global Renderer for the duration of the test.
+func setRenderer(t *testing.T, r Renderer) {
+	t.Helper()
+	prev := renderer.Load()
+	SetRenderer(r)
+	t.Cleanup(func() { renderer.Store(prev) })
+}
+
+func TestRenderer(t *testing.T) {
+	base := New("request failed")
+	fields := []field{
+		String("path", "/a b"),
+		String("note", "x, k: v"),
+		String("", "value only"),
+		Int("attempt", 2),
+		String("empty", ""),
+		String("multi line", "a\nb"),
+	}
+
+	tests := []struct {
+		name string
+		r    Renderer
+		want string
+	}{
+		{"zero", Renderer{}, "request failed, path: /a b, note: x, k: v, value only, attempt: 2, empty: , multi line: a\nb"},
+		{"separators", Renderer{FieldSeparator: "; ", KeyValueSeparator: "="},
+			"request failed; path=/a b; note=x, k: v; value only; attempt=2; empty=; multi line=a\nb"},
+		{"quote", Renderer{Quote: true},
+			`request failed, path: "/a b", note: "x, k: v", "value only", attempt: 2, empty: "", "multi line": "a\nb"`},
+		{"escape", Renderer{Escape: true}, `request failed, path: /a b, note: x, k: v, value only, attempt: 2, empty: , multi line: a\nb`},
+		{"logfmt", Logfmt, `request failed path="/a b" note="x, k: v" "value only" attempt=2 empty="" "multi line"="a\nb"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.r
+			if got := r.With(base, fields...).Error(); got != tt.want {
+				t.Fatalf("Error() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderer_quoteSeparators(t *testing.T) {
+	r := Renderer{FieldSeparator: " | ", KeyValueSeparator: "=", Quote: true}
+	err := r.With(New("failed"), String("a", "x|y"), String("b", "x | y"), String("c", "k=v"), String("d", `say "hi"`))
+	if want := `failed | a=x|y | b="x | y" | c="k=v" | d="say \"hi\""`; err.Error() != want {
+		t.Fatalf("Error() = %s, want %s", err.Error(), want)
+	}
+}
+
+func TestSetRenderer(t *testing.T) {
+	err := With(New("failed"), String("k", "a b"))
+	own := Renderer{KeyValueSeparator: "->"}
+	ownErr := own.With(New("failed"), String("k", "a b"))
+
+	setRenderer(t, Logfmt)
+	if want := `failed k="a b"`; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+	if want := "failed, k->a b"; ownErr.Error() != want {
+		t.Fatalf("Error() = %q, want %q: Renderer.With must keep its renderer", ownErr.Error(), want)
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, "failed k=\"a b\"\n\tk=\"a b\"") {
+		t.Fatalf("%%+v = %q", got)
+	}
+
+	SetRenderer(Renderer{})
+	if want := "failed, k: a b"; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q after restoring the default", err.Error(), want)
+	}
+}
+
+func TestRenderer_With(t *testing.T) {
+	if Logfmt.With(nil, String("k", "v")) != nil {
+		t.Fatalf("With(nil) != nil")
+	}
+	base := New("x")
+	if Logfmt.With(base) != base {
+		t.Fatalf("With() without fields did not return the original error")
+	}
+}
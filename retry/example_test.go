@@ -0,0 +1,27 @@
+package retry_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ygrebnov/errorc"
+	"github.com/ygrebnov/errorc/retry"
+)
+
+func ExampleDo() {
+	errUnavailable := errorc.New("unavailable", errorc.WithClass(errorc.Temporary))
+	errInvalid := errorc.New("invalid", errorc.WithClass(errorc.Permanent))
+
+	calls := 0
+	err := retry.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return errorc.With(errUnavailable, errorc.RetryAfter(time.Millisecond))
+		}
+		return errorc.With(errInvalid, errorc.String("field", "name"))
+	}, retry.Policy{MaxAttempts: 5})
+
+	fmt.Println(err)
+	// Output: retry: giving up after 2 attempts: invalid, field: name, attempt: 2
+}
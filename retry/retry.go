@@ -0,0 +1,195 @@
+// Package retry retries operations failing with errors classified by
+// github.com/ygrebnov/errorc.
+//
+// Do calls a function until it succeeds, waiting with exponential backoff and jitter
+// between attempts. It stops on errors classified as errorc.Permanent and waits as long
+// as requested by an errorc.RetryAfter field:
+//
+//	err := retry.Do(ctx, func(ctx context.Context) error {
+//		return client.Put(ctx, key, value)
+//	}, retry.Policy{MaxAttempts: 5})
+//	// retry: giving up after 5 attempts: storage: unavailable, attempt: 5
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// AttemptKey is the key of the errorc.Int field holding the attempt number, starting
+// at 1, attached to the error of every attempt.
+const AttemptKey = "attempt"
+
+// Default values of the Policy fields.
+const (
+	DefaultMaxAttempts  = 3
+	DefaultInitialDelay = 100 * time.Millisecond
+	DefaultMaxDelay     = 10 * time.Second
+	DefaultMultiplier   = 2
+	DefaultJitter       = 0.5
+)
+
+// Policy configures Do. The zero value selects the defaults.
+type Policy struct {
+	// MaxAttempts is the maximum number of calls. Zero selects DefaultMaxAttempts.
+	MaxAttempts int
+	// InitialDelay is the delay before the second attempt. Zero selects DefaultInitialDelay.
+	InitialDelay time.Duration
+	// MaxDelay caps the delays computed by the backoff. Zero selects DefaultMaxDelay.
+	// Delays requested with errorc.RetryAfter are not capped.
+	MaxDelay time.Duration
+	// Multiplier is the factor applied to the delay after every attempt. Values less than
+	// 1 select DefaultMultiplier.
+	Multiplier float64
+	// Jitter is the fraction of every computed delay that is randomized: a delay d is
+	// replaced by a random duration between d*(1-Jitter) and d. Zero selects DefaultJitter;
+	// a negative value disables jitter. Values greater than 1 are treated as 1.
+	Jitter float64
+	// Clock waits between attempts. Nil selects the system clock.
+	Clock Clock
+	// Rand returns a random number in [0, 1) used for jitter. Nil selects math/rand/v2.
+	Rand func() float64
+}
+
+// Clock waits between attempts. Tests can provide a fake implementation to run
+// without waiting.
+type Clock interface {
+	// After returns a channel receiving a value once d has elapsed, like time.After.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// withDefaults returns p with the zero fields replaced with their defaults.
+func (p Policy) withDefaults() Policy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = DefaultInitialDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultMaxDelay
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = DefaultMultiplier
+	}
+	switch {
+	case p.Jitter == 0:
+		p.Jitter = DefaultJitter
+	case p.Jitter < 0:
+		p.Jitter = 0
+	case p.Jitter > 1:
+		p.Jitter = 1
+	}
+	if p.Clock == nil {
+		p.Clock = systemClock{}
+	}
+	if p.Rand == nil {
+		p.Rand = rand.Float64
+	}
+	return p
+}
+
+// Delay returns the delay computed by the backoff before the given attempt, starting at 2,
+// including jitter.
+func (p Policy) Delay(attempt int) time.Duration {
+	return p.withDefaults().delay(attempt)
+}
+
+// delay implements Delay for a policy returned by withDefaults.
+func (p Policy) delay(attempt int) time.Duration {
+	d := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(attempt-2))
+	if d > float64(p.MaxDelay) || math.IsNaN(d) {
+		d = float64(p.MaxDelay)
+	}
+	d -= d * p.Jitter * p.Rand()
+	return time.Duration(d)
+}
+
+// Do calls fn until it returns nil, at most p.MaxAttempts times, and returns nil if it
+// succeeds. After a failed attempt, Do waits before the next one:
+//   - as long as requested by the errorc.RetryAfter field of the error, if any;
+//   - otherwise, for the delay computed by the exponential backoff of p.
+//
+// Do stops retrying if the error is classified as errorc.Permanent, if the attempts are
+// exhausted, or if ctx is done. It then returns an *Error holding the error of every
+// attempt. If ctx is done before the first attempt, Do returns ctx.Err().
+func Do(ctx context.Context, fn func(ctx context.Context) error, p Policy) error {
+	p = p.withDefaults()
+	var attempts []error
+	for n := 1; ; n++ {
+		if err := ctx.Err(); err != nil {
+			if len(attempts) == 0 {
+				return err
+			}
+			return &Error{Attempts: attempts, ctxErr: err}
+		}
+
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		attempts = append(attempts, errorc.With(err, errorc.Int(AttemptKey, n)))
+		if n >= p.MaxAttempts || errorc.ClassOf(err) == errorc.Permanent {
+			return &Error{Attempts: attempts}
+		}
+
+		d, ok := errorc.RetryDelay(err)
+		if !ok {
+			d = p.delay(n + 1)
+		}
+		select {
+		case <-ctx.Done():
+			return &Error{Attempts: attempts, ctxErr: ctx.Err()}
+		case <-p.Clock.After(d):
+		}
+	}
+}
+
+// Error is the error returned by Do when no attempt succeeded. It wraps the error of every
+// attempt, so errors.Is and errors.As match any of them.
+type Error struct {
+	// Attempts holds the errors of the attempts, in order. Each one wraps the error
+	// returned by the function with an errorc.Int field holding the attempt number under
+	// AttemptKey.
+	Attempts []error
+
+	ctxErr error // set if Do stopped because the context was done
+}
+
+// Error returns the message of the last attempt, prefixed with the reason Do stopped.
+func (e *Error) Error() string {
+	n, last := len(e.Attempts), e.Last()
+	attempts := "attempts"
+	if n == 1 {
+		attempts = "attempt"
+	}
+	if e.ctxErr != nil {
+		return fmt.Sprintf("retry: %v after %d %s: %v", e.ctxErr, n, attempts, last)
+	}
+	return fmt.Sprintf("retry: giving up after %d %s: %v", n, attempts, last)
+}
+
+// Unwrap returns the errors of the attempts, followed by the error of the context if Do
+// stopped because it was done.
+func (e *Error) Unwrap() []error {
+	if e.ctxErr == nil {
+		return e.Attempts
+	}
+	return append(e.Attempts[:len(e.Attempts):len(e.Attempts)], e.ctxErr)
+}
+
+// Last returns the error of the last attempt.
+func (e *Error) Last() error {
+	return e.Attempts[len(e.Attempts)-1]
+}
@@ -0,0 +1,175 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ygrebnov/errorc"
+	"github.com/ygrebnov/errorc/retry"
+)
+
+var (
+	errUnavailable = errorc.New("unavailable", errorc.WithClass(errorc.Temporary))
+	errInvalid     = errorc.New("invalid", errorc.WithClass(errorc.Permanent))
+	errUnknown     = errorc.New("unknown")
+)
+
+// fakeClock records the delays it is asked to wait and fires immediately.
+type fakeClock struct {
+	delays []time.Duration
+	// onAfter is called before firing, for example to cancel a context.
+	onAfter func()
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	if c.onAfter != nil {
+		c.onAfter()
+		// Never fire, so that the context wins.
+		return nil
+	}
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+// failing returns a function failing with the given errors, then succeeding,
+// and a pointer to the number of calls.
+func failing(errs ...error) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls <= len(errs) {
+			return errs[calls-1]
+		}
+		return nil
+	}, &calls
+}
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name       string
+		errs       []error
+		policy     retry.Policy
+		wantCalls  int
+		wantDelays []time.Duration
+		wantErr    string
+	}{
+		{"success", nil, retry.Policy{}, 1, nil, ""},
+		{"retried until success", []error{errUnavailable, errUnknown}, retry.Policy{}, 3,
+			[]time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, ""},
+		{"exhausted", []error{errUnavailable, errUnavailable, errUnavailable}, retry.Policy{}, 3,
+			[]time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			"retry: giving up after 3 attempts: unavailable, attempt: 3"},
+		{"permanent", []error{errUnavailable, errorc.With(errInvalid, errorc.String("id", "1"))}, retry.Policy{}, 2,
+			[]time.Duration{100 * time.Millisecond}, "retry: giving up after 2 attempts: invalid, id: 1, attempt: 2"},
+		{"classified permanent by With", []error{errorc.With(errUnavailable, errorc.Classify(errorc.Permanent))}, retry.Policy{}, 1,
+			nil, "retry: giving up after 1 attempt: unavailable, attempt: 1"},
+		{"retry after", []error{errorc.With(errUnavailable, errorc.RetryAfter(time.Minute)), errUnavailable}, retry.Policy{}, 3,
+			[]time.Duration{time.Minute, 200 * time.Millisecond}, ""},
+		{"max delay", []error{errUnavailable, errUnavailable, errUnavailable, errUnavailable},
+			retry.Policy{MaxAttempts: 5, InitialDelay: time.Second, MaxDelay: 3 * time.Second, Multiplier: 3}, 5,
+			[]time.Duration{time.Second, 3 * time.Second, 3 * time.Second, 3 * time.Second}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{}
+			tt.policy.Clock = clock
+			tt.policy.Jitter = -1
+			fn, calls := failing(tt.errs...)
+
+			err := retry.Do(context.Background(), fn, tt.policy)
+			if *calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", *calls, tt.wantCalls)
+			}
+			if !reflect.DeepEqual(clock.delays, tt.wantDelays) {
+				t.Errorf("delays = %v, want %v", clock.delays, tt.wantDelays)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Do returned %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Do returned %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDo_error(t *testing.T) {
+	fn, _ := failing(errUnknown, errUnavailable, errUnavailable)
+	err := retry.Do(context.Background(), fn, retry.Policy{Clock: &fakeClock{}})
+
+	var rerr *retry.Error
+	if !errors.As(err, &rerr) {
+		t.Fatalf("Do returned %T, want *retry.Error", err)
+	}
+	if len(rerr.Attempts) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(rerr.Attempts))
+	}
+	for i, attempt := range rerr.Attempts {
+		f, ok := errorc.Lookup(attempt, retry.AttemptKey)
+		if !ok || f.Int64() != int64(i+1) {
+			t.Errorf("attempt %d: attempt field = %v, %v", i+1, f, ok)
+		}
+	}
+	if !errors.Is(err, errUnknown) || !errors.Is(err, errUnavailable) {
+		t.Errorf("errors.Is does not match the errors of the attempts")
+	}
+	if rerr.Last() != rerr.Attempts[2] {
+		t.Errorf("Last() is not the last attempt")
+	}
+}
+
+func TestDo_context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fn, calls := failing(errUnavailable, errUnavailable)
+	err := retry.Do(ctx, fn, retry.Policy{Clock: &fakeClock{onAfter: cancel}})
+
+	if *calls != 1 {
+		t.Errorf("got %d calls, want 1", *calls)
+	}
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, errUnavailable) {
+		t.Fatalf("Do returned %v, want it to match context.Canceled and the attempt error", err)
+	}
+	if want := "retry: context canceled after 1 attempt: unavailable, attempt: 1"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+
+	fn, calls = failing()
+	if err := retry.Do(ctx, fn, retry.Policy{}); err != context.Canceled || *calls != 0 {
+		t.Errorf("Do with a done context = %v after %d calls, want context.Canceled after none", err, *calls)
+	}
+}
+
+func TestPolicy_Delay(t *testing.T) {
+	p := retry.Policy{InitialDelay: time.Second, Rand: func() float64 { return 0.5 }}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{2, 750 * time.Millisecond},
+		{3, 1500 * time.Millisecond},
+		{4, 3 * time.Second},
+		{100, 7500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := p.Delay(tt.attempt); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+
+	p.Jitter = 2
+	if got := p.Delay(2); got != 500*time.Millisecond {
+		t.Errorf("Delay with Jitter 2 = %v, want 500ms", got)
+	}
+	p.Jitter = -1
+	if got := p.Delay(2); got != time.Second {
+		t.Errorf("Delay without jitter = %v, want 1s", got)
+	}
+}
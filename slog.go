@@ -0,0 +1,65 @@
+package errorc
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer. The error is logged as a group containing
+// the message of the wrapped error under the "msg" key, followed by each field
+// attached by this and directly nested With calls as its own attribute.
+func (e *errorWithFields) LogValue() slog.Value {
+	var layers []*errorWithFields
+	var err error = e
+	n := 0
+	for {
+		ewf, ok := err.(*errorWithFields)
+		if !ok {
+			break
+		}
+		layers = append(layers, ewf)
+		n += len(ewf.f)
+		err = ewf.e
+	}
+
+	attrs := make([]slog.Attr, 0, n+1)
+	attrs = append(attrs, slog.String(slog.MessageKey, err.Error()))
+	for i := len(layers) - 1; i >= 0; i-- {
+		for _, f := range layers[i].f {
+			sf := f.get()
+			if sf, ok := sf.redactFor(OutputSlog); ok {
+				attrs = append(attrs, sf.attr())
+			}
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Attrs converts the fields attached by With to err and to every error in its
+// Unwrap chain into slog attributes, in the same order as Fields.
+// Fields with an empty key produce attributes with an empty key.
+// It returns nil if err is nil or carries no fields.
+func Attrs(err error) []slog.Attr {
+	fields := Fields(err)
+	if len(fields) == 0 {
+		return nil
+	}
+	attrs := make([]slog.Attr, 0, len(fields))
+	for i := range fields {
+		if f, ok := fields[i].redactFor(OutputSlog); ok {
+			attrs = append(attrs, f.attr())
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	return attrs
+}
+
+func (s Field) attr() slog.Attr {
+	switch s.kind {
+	case KindString:
+		return slog.String(s.key, s.str)
+	case KindError:
+		return slog.Any(s.key, s.any)
+	default:
+		return slog.Any(s.key, s.Any())
+	}
+}
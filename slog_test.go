@@ -0,0 +1,66 @@
+package errorc
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	err := With(With(New("base"), String("user_id", "42")), Int("count", 3))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Error("request failed", slog.Any("error", err))
+
+	want := `{"level":"ERROR","msg":"request failed","error":{"msg":"base","user_id":"42","count":3}}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestLogValue_nonFieldLayer(t *testing.T) {
+	inner := With(New("base"), String("a", "1"))
+	err := With(fmt.Errorf("handler: %w", inner), String("b", "2"))
+
+	v := err.(slog.LogValuer).LogValue()
+	attrs := v.Group()
+	if len(attrs) != 2 {
+		t.Fatalf("got %d attrs, want 2", len(attrs))
+	}
+	if attrs[0].Key != "msg" || attrs[0].Value.String() != "handler: base, a: 1" {
+		t.Errorf("attrs[0] = %v, want msg=handler: base, a: 1", attrs[0])
+	}
+	if attrs[1].Key != "b" || attrs[1].Value.String() != "2" {
+		t.Errorf("attrs[1] = %v, want b=2", attrs[1])
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	if got := Attrs(New("base")); got != nil {
+		t.Fatalf("Attrs(plain error) = %v, want nil", got)
+	}
+
+	inner := With(New("base"), String("a", "1"))
+	err := With(fmt.Errorf("handler: %w", inner), Bool("b", true), String("", "v"))
+
+	got := Attrs(err)
+	want := []slog.Attr{slog.String("a", "1"), slog.Bool("b", true), slog.String("", "v")}
+	if len(got) != len(want) {
+		t.Fatalf("got %d attrs, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("Attrs()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package errorc
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+)
+
+// maxStackDepth limits the number of frames recorded for a stack trace.
+const maxStackDepth = 32
+
+// stackTraces enables stack capture in every New and With call.
+var stackTraces atomic.Bool
+
+// SetStackTraces enables or disables recording the stack of every New and With call.
+// It is disabled by default, because capturing a stack makes With several times slower.
+// Only errors created after the call are affected; use Stack or WithStack to record the
+// stack of a single error.
+func SetStackTraces(enabled bool) {
+	stackTraces.Store(enabled)
+}
+
+// Stack creates a field recording the stack of its caller, so that a single With call
+// captures a stack regardless of SetStackTraces:
+//
+//	err := With(ErrNotFound, Stack(), String("id", id))
+//
+// The field is not rendered and is not returned by Fields or Lookup.
+// The stack can be retrieved with StackTrace.
+func Stack() field {
+	var stack any = callers(3)
+	return func() Field {
+		return Field{kind: kindStack, any: stack}
+	}
+}
+
+// StackTrace returns the frames of the stack recorded for err or for any error in its
+// Unwrap chain. If several With layers of the chain carry a stack, the innermost one is
+// returned, since it is the closest to where the failure originated. The stack recorded by
+// New is returned only if no With layer carries one, because errors created by New are
+// usually package-level sentinels whose stack points to package initialization.
+// It returns nil if no stack was recorded.
+func StackTrace(err error) []runtime.Frame {
+	return framesOf(stackOf(err))
+}
+
+// framesOf resolves program counters to frames.
+func framesOf(pcs []uintptr) []runtime.Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(pcs)
+	result := make([]runtime.Frame, 0, len(pcs))
+	for {
+		frame, more := frames.Next()
+		result = append(result, frame)
+		if !more {
+			break
+		}
+	}
+	return result
+}
+
+// stackOf returns the innermost stack recorded by With in the Unwrap chain of err, or else
+// the innermost stack recorded by New.
+func stackOf(err error) []uintptr {
+	var pcs, created []uintptr
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case *errorWithFields:
+			if e.stack != nil {
+				pcs = e.stack
+			}
+		case *sentinel:
+			if e.stack != nil {
+				created = e.stack
+			}
+		}
+	}
+	if pcs == nil {
+		return created
+	}
+	return pcs
+}
+
+// layerStack returns the stack recorded by err itself, ignoring the errors it wraps.
+func layerStack(err error) []uintptr {
+	switch e := err.(type) {
+	case *sentinel:
+		return e.stack
+	case *errorWithFields:
+		return e.stack
+	}
+	return nil
+}
+
+// callers returns the program counters of the stack, skipping the given number of frames.
+// skip 0 identifies the frame of runtime.Callers itself and skip 1 the caller of callers.
+func callers(skip int) []uintptr {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip, pcs[:])
+	stack := make([]uintptr, n)
+	copy(stack, pcs[:n])
+	return stack
+}
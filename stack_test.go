@@ -0,0 +1,101 @@
+package errorc
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+const thisFunc = "github.com/ygrebnov/errorc.TestStackTrace"
+
+func TestStackTrace(t *testing.T) {
+	if StackTrace(nil) != nil {
+		t.Fatalf("StackTrace(nil) should be nil")
+	}
+	if StackTrace(With(New("base"), String("k", "v"))) != nil {
+		t.Fatalf("StackTrace() should be nil when stacks are disabled")
+	}
+
+	t.Run("Stack field", func(t *testing.T) {
+		err := With(New("base"), Stack(), String("k", "v"))
+		if got := err.Error(); got != "base, k: v" {
+			t.Fatalf("Error() = %q, want 'base, k: v'", got)
+		}
+		if got := len(Fields(err)); got != 1 {
+			t.Fatalf("Fields() returned %d fields, want 1", got)
+		}
+		if _, ok := Lookup(err, ""); ok {
+			t.Fatalf("Lookup() returned the Stack field")
+		}
+		assertTopFrame(t, StackTrace(err), thisFunc+".func1")
+	})
+
+	t.Run("WithStack option", func(t *testing.T) {
+		err := With(New("base", WithStack()), String("k", "v"))
+		assertTopFrame(t, StackTrace(err), thisFunc+".func2")
+	})
+
+	t.Run("innermost stack wins", func(t *testing.T) {
+		inner := With(New("inner"), Stack())
+		err := With(inner, Stack())
+		assertTopFrame(t, StackTrace(err), thisFunc+".func3")
+		assertTopFrame(t, StackTrace(fmt.Errorf("outer: %w", inner)), thisFunc+".func3")
+	})
+
+	t.Run("SetStackTraces", func(t *testing.T) {
+		SetStackTraces(true)
+		defer SetStackTraces(false)
+
+		sentinel := New("base")
+		assertTopFrame(t, StackTrace(sentinel), thisFunc+".func4")
+		err := With(fmt.Errorf("wrapped: %w", New("")), String("k", "v"))
+		assertTopFrame(t, StackTrace(err), thisFunc+".func4")
+		err = Logfmt.With(fmt.Errorf("wrapped: %w", New("")), String("k", "v"))
+		assertTopFrame(t, StackTrace(err), thisFunc+".func4")
+		err = WithContext(ContextWith(context.Background(), String("id", "1")), fmt.Errorf("wrapped: %w", New("")))
+		assertTopFrame(t, StackTrace(err), thisFunc+".func4")
+	})
+
+	t.Run("NewError and ErrorFactory", func(t *testing.T) {
+		assertTopFrame(t, StackTrace(Namespace("ns").NewError("base", WithStack())), thisFunc+".func5")
+
+		SetStackTraces(true)
+		defer SetStackTraces(false)
+
+		assertTopFrame(t, StackTrace(Namespace("ns").NewError("base")), thisFunc+".func5")
+		assertTopFrame(t, StackTrace(ErrorFactory("ns")("base")), thisFunc+".func5")
+	})
+
+	t.Run("With stack wins over New stack", func(t *testing.T) {
+		assertTopFrame(t, StackTrace(newStackErr()), "github.com/ygrebnov/errorc.newStackErr")
+		assertTopFrame(t, StackTrace(withStack()), "github.com/ygrebnov/errorc.withStack")
+		assertTopFrame(t, StackTrace(With(withStack(), Stack())), "github.com/ygrebnov/errorc.withStack")
+
+		SetStackTraces(true)
+		defer SetStackTraces(false)
+
+		assertTopFrame(t, StackTrace(With(errPackageLevel, String("k", "v"))), thisFunc+".func6")
+	})
+}
+
+func newStackErr() error {
+	return New("inner", WithStack())
+}
+
+// errPackageLevel records the stack of package initialization.
+var errPackageLevel = New("package level", WithStack())
+
+func withStack() error {
+	return With(errPackageLevel, Stack())
+}
+
+func assertTopFrame(t *testing.T, frames []runtime.Frame, function string) {
+	t.Helper()
+	if len(frames) == 0 {
+		t.Fatalf("no stack recorded")
+	}
+	if frames[0].Function != function {
+		t.Fatalf("top frame = %s, want %s", frames[0].Function, function)
+	}
+}
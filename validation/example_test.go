@@ -0,0 +1,36 @@
+package validation_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ygrebnov/errorc/validation"
+)
+
+type signup struct {
+	Name string
+	User struct{ Email string }
+}
+
+func (s signup) validate() error {
+	var v validation.Validator
+	v.Check(s.Name != "", "name", "required")
+	user := v.Nested("user")
+	user.Check(strings.Contains(s.User.Email, "@"), "email", "must be valid")
+	return v.Err()
+}
+
+func ExampleValidator() {
+	err := signup{}.validate()
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, validation.ErrInvalidInput))
+
+	b, _ := json.Marshal(err)
+	fmt.Println(string(b))
+	// Output:
+	// invalid input, name: required, user.email: must be valid
+	// true
+	// {"message":"invalid input","code":"invalid_input","violations":[{"path":"name","message":"required"},{"path":"user.email","message":"must be valid"}]}
+}
@@ -0,0 +1,166 @@
+// Package validation collects field-level violations found while validating request
+// payloads into a single error built with github.com/ygrebnov/errorc.
+//
+// Violation paths are structured keys created with github.com/ygrebnov/keys. A Validator
+// collects violations, and nested validators prefix the paths of their violations:
+//
+//	var v validation.Validator
+//	v.Check(req.Name != "", "name", "required")
+//	user := v.Nested("user")
+//	user.Check(strings.Contains(req.User.Email, "@"), "email", "must be valid")
+//	if err := v.Err(); err != nil {
+//		return err // invalid input, name: required, user.email: must be valid
+//	}
+//
+// The error matches ErrInvalidInput with errors.Is, carries every violation as an errorc
+// field, and is encoded by encoding/json as a list of violations.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ygrebnov/errorc"
+	"github.com/ygrebnov/errorc/httperr"
+	"github.com/ygrebnov/keys"
+)
+
+// ErrInvalidInput is the error matched by the errors returned by Validator.Err. Its code is
+// "invalid_input", its class errorc.Permanent, and its HTTP status 400 Bad Request.
+var ErrInvalidInput = errorc.New("invalid input",
+	errorc.WithCode("invalid_input"),
+	errorc.WithClass(errorc.Permanent),
+	httperr.WithStatus(http.StatusBadRequest),
+)
+
+// Violation is a constraint violated by the value at a path.
+type Violation struct {
+	// Path identifies the value, for example "user.email". It is empty for a violation
+	// of the whole payload.
+	Path keys.Key `json:"path"`
+	// Message describes the violated constraint, for example "must be valid".
+	Message string `json:"message"`
+}
+
+// String returns the violation as "path: message", or the message alone if the path is empty.
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return string(v.Path) + ": " + v.Message
+}
+
+// Validator collects violations. The zero value is ready to use. A Validator must not be
+// used concurrently.
+type Validator struct {
+	segments   []keys.Segment
+	violations *[]Violation
+}
+
+// Nested returns a Validator adding its violations to v, with paths prefixed by segments:
+//
+//	v.Nested("user").Add("email", "must be valid") // user.email: must be valid
+//	v.Nested("items", "0").Add("sku", "required")  // items.0.sku: required
+func (v *Validator) Nested(segments ...keys.Segment) *Validator {
+	if v.violations == nil {
+		v.violations = new([]Violation)
+	}
+	s := make([]keys.Segment, 0, len(v.segments)+len(segments))
+	s = append(s, v.segments...)
+	return &Validator{segments: append(s, segments...), violations: v.violations}
+}
+
+// Add adds a violation of the value at path, relative to the segments of v.
+func (v *Validator) Add(path keys.Key, message string) {
+	if v.violations == nil {
+		v.violations = new([]Violation)
+	}
+	if len(v.segments) > 0 {
+		path = keys.New(string(path), keys.WithSegments(v.segments...))
+	}
+	*v.violations = append(*v.violations, Violation{Path: path, Message: message})
+}
+
+// Addf is like Add with a message formatted by fmt.Sprintf.
+func (v *Validator) Addf(path keys.Key, format string, args ...any) {
+	v.Add(path, fmt.Sprintf(format, args...))
+}
+
+// Check adds a violation of the value at path if ok is false, and returns ok.
+func (v *Validator) Check(ok bool, path keys.Key, message string) bool {
+	if !ok {
+		v.Add(path, message)
+	}
+	return ok
+}
+
+// Valid reports whether no violation was added to v or to any Validator sharing its violations.
+func (v *Validator) Valid() bool {
+	return v.violations == nil || len(*v.violations) == 0
+}
+
+// Err returns nil if no violation was added. Otherwise, it returns an *Error wrapping
+// ErrInvalidInput with one errorc.String field per violation, keyed by its path, in the
+// order the violations were added:
+//
+//	invalid input, name: required, user.email: must be valid
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	violations := append([]Violation(nil), *v.violations...)
+	return newError(violations)
+}
+
+func newError(violations []Violation) *Error {
+	fields := make([]errorc.Field, len(violations))
+	for i, vi := range violations {
+		fields[i] = errorc.String(vi.Path, vi.Message).Field()
+	}
+	return &Error{err: errorc.WithFields(ErrInvalidInput, fields), violations: violations}
+}
+
+// Error is the error returned by Validator.Err.
+type Error struct {
+	err        error // ErrInvalidInput wrapped with the violations
+	violations []Violation
+}
+
+// Error returns the message of ErrInvalidInput followed by the violations.
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns ErrInvalidInput wrapped by an errorc.WithFields call attaching the violations as fields.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Violations returns a copy of the violations.
+func (e *Error) Violations() []Violation {
+	return append([]Violation(nil), e.violations...)
+}
+
+// MarshalJSON implements json.Marshaler. The error is encoded as an object holding the
+// message and code of ErrInvalidInput and the list of violations:
+//
+//	{"message":"invalid input","code":"invalid_input","violations":[{"path":"user.email","message":"must be valid"}]}
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Message    string      `json:"message"`
+		Code       string      `json:"code"`
+		Violations []Violation `json:"violations"`
+	}{ErrInvalidInput.Error(), errorc.Code(ErrInvalidInput), e.violations})
+}
+
+// Violations returns the violations of the nearest *Error in the tree of errors wrapped by
+// err, or nil if there is none.
+func Violations(err error) []Violation {
+	var e *Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+	return e.Violations()
+}
@@ -0,0 +1,109 @@
+package validation_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ygrebnov/errorc"
+	"github.com/ygrebnov/errorc/httperr"
+	"github.com/ygrebnov/errorc/validation"
+	"github.com/ygrebnov/keys"
+)
+
+func TestValidator(t *testing.T) {
+	var v validation.Validator
+	if err := v.Err(); err != nil || !v.Valid() {
+		t.Fatalf("zero Validator: Err() = %v, Valid() = %v", err, v.Valid())
+	}
+
+	if !v.Check(true, "name", "required") {
+		t.Errorf("Check(true) = false")
+	}
+	if v.Check(false, "name", "required") {
+		t.Errorf("Check(false) = true")
+	}
+	user := v.Nested("user")
+	user.Add("email", "must be valid")
+	user.Nested("address").Addf("zip", "must have %d digits", 5)
+	v.Nested("items", "0").Add(keys.New("sku"), "required")
+	v.Add("", "too many fields")
+
+	if v.Valid() || user.Valid() {
+		t.Fatalf("Valid() = true after violations were added")
+	}
+	err := v.Err()
+	want := "invalid input, name: required, user.email: must be valid, user.address.zip: must have 5 digits, items.0.sku: required, too many fields"
+	if err == nil || err.Error() != want {
+		t.Fatalf("Err() = %v, want %s", err, want)
+	}
+	if got := user.Err(); got == nil || got.Error() != want {
+		t.Errorf("nested Err() = %v, want the same violations", got)
+	}
+
+	wantViolations := []validation.Violation{
+		{Path: "name", Message: "required"},
+		{Path: "user.email", Message: "must be valid"},
+		{Path: "user.address.zip", Message: "must have 5 digits"},
+		{Path: "items.0.sku", Message: "required"},
+		{Path: "", Message: "too many fields"},
+	}
+	if got := validation.Violations(err); !reflect.DeepEqual(got, wantViolations) {
+		t.Errorf("Violations = %v, want %v", got, wantViolations)
+	}
+
+	// Violations added after Err are not reflected in the returned error.
+	v.Add("late", "ignored")
+	if got := validation.Violations(err); len(got) != len(wantViolations) {
+		t.Errorf("got %d violations after a later Add, want %d", len(got), len(wantViolations))
+	}
+}
+
+func TestError(t *testing.T) {
+	var v validation.Validator
+	v.Add("user.email", "must be valid")
+	err := v.Err()
+
+	var two validation.Validator
+	two.Add("name", "required")
+	two.Add("age", "must be positive")
+	want := "invalid input, name: required, age: must be positive\n\tname: required\n\tage: must be positive\ncaused by: invalid input"
+	if got := fmt.Sprintf("%+v", errors.Unwrap(two.Err())); got != want {
+		t.Errorf("%%+v = %q, want %q", got, want)
+	}
+
+	wrapped := errorc.With(err, errorc.String("request_id", "r1"))
+	if !errors.Is(wrapped, validation.ErrInvalidInput) {
+		t.Errorf("errors.Is(err, ErrInvalidInput) = false")
+	}
+	if got := validation.Violations(wrapped); len(got) != 1 || got[0].String() != "user.email: must be valid" {
+		t.Errorf("Violations(wrapped) = %v", got)
+	}
+	if validation.Violations(errors.New("other")) != nil {
+		t.Errorf("Violations of another error is not nil")
+	}
+	if f, ok := errorc.Lookup(err, "user.email"); !ok || f.Value() != "must be valid" {
+		t.Errorf("Lookup(user.email) = %v, %v", f, ok)
+	}
+	if errorc.Code(err) != "invalid_input" || errorc.IsRetryable(err) || errorc.ClassOf(err) != errorc.Permanent {
+		t.Errorf("code %q, class %s", errorc.Code(err), errorc.ClassOf(err))
+	}
+	if got := httperr.Status(err); got != http.StatusBadRequest {
+		t.Errorf("httperr.Status = %d, want 400", got)
+	}
+
+	b, jerr := json.Marshal(err)
+	want = `{"message":"invalid input","code":"invalid_input","violations":[{"path":"user.email","message":"must be valid"}]}`
+	if jerr != nil || string(b) != want {
+		t.Errorf("json.Marshal = %s, %v, want %s", b, jerr, want)
+	}
+}
+
+func TestViolation_String(t *testing.T) {
+	if got := (validation.Violation{Message: "required"}).String(); got != "required" {
+		t.Errorf("String() without path = %q", got)
+	}
+}